@@ -1,18 +1,28 @@
 package zkp
 
 import (
+	"strconv"
 	"testing"
 
 	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/graph/gen"
+	"github.com/stretchr/testify/assert"
 )
 
+// createCircularGraph returns a cycle colored with two alternating colors, and
+// a third color on the last node when the cycle is odd.
 func createCircularGraph(nodesCount int) *coloringgraph.ColoringGraph {
 	graph := coloringgraph.NewColoringGraph()
 
-	colors := []string{"red", "blue", "green", "yellow", "purple", "orange", "pink", "brown", "gray", "black"}
+	colors := []string{"red", "blue", "green"}
 
 	for i := 0; i < nodesCount; i++ {
-		graph.AddNode(coloringgraph.ColorNodeValue(colors[i%len(colors)]))
+		color := colors[i%2]
+		if nodesCount%2 == 1 && i == nodesCount-1 {
+			color = colors[2]
+		}
+		graph.AddNode(coloringgraph.ColorNodeValue(color))
+		graph.Colors[color] = struct{}{}
 	}
 
 	for i := 0; i < nodesCount; i++ {
@@ -21,6 +31,25 @@ func createCircularGraph(nodesCount int) *coloringgraph.ColoringGraph {
 	return graph
 }
 
+// createPlantedGraph returns a random graph with about edgesCount edges,
+// colored with the k colors it was generated with.
+func createPlantedGraph(nodesCount, edgesCount, k int) *coloringgraph.ColoringGraph {
+	// Edges are only drawn between the (k-1)/k of the pairs with different colors
+	p := float64(edgesCount) / (float64(nodesCount) * float64(nodesCount-1) / 2 * float64(k-1) / float64(k))
+	g, witness, err := gen.Planted[coloringgraph.ColorNodeValue](nodesCount, k, p, 1)
+	if err != nil {
+		panic(err)
+	}
+
+	graph := &coloringgraph.ColoringGraph{Graph: g, Colors: make(map[string]struct{})}
+	for v, c := range witness {
+		color := strconv.Itoa(c)
+		graph.ReplaceNodeValue(v, coloringgraph.ColorNodeValue(color))
+		graph.Colors[color] = struct{}{}
+	}
+	return graph
+}
+
 func BenchmarkProofCreation(b *testing.B) {
 	// Create a test graph with 10 nodes
 	graph := createCircularGraph(10000)
@@ -33,6 +62,17 @@ func BenchmarkProofCreation(b *testing.B) {
 	}
 }
 
+func BenchmarkProofCreationPlantedGraph(b *testing.B) {
+	graph := createPlantedGraph(1000, 10000, 4)
+
+	proofer := NewProofer(graph)
+
+	b.ResetTimer()
+	for b.Loop() {
+		proofer.CreateProof(100)
+	}
+}
+
 func BenchmarkProofVerification(b *testing.B) {
 	// Create a test graph with 10 nodes
 	graph := createCircularGraph(100)
@@ -58,6 +98,17 @@ func BenchmarkProofCreationLargeGraph(b *testing.B) {
 	}
 }
 
+func BenchmarkProofCreationLargeGraphSequential(b *testing.B) {
+	graph := createCircularGraph(10000)
+
+	proofer := NewProofer(graph, WithConcurrency(1))
+
+	b.ResetTimer()
+	for b.Loop() {
+		proofer.CreateProof(10000)
+	}
+}
+
 func BenchmarkProofVerificationLargeGraph(b *testing.B) {
 	// Create a test graph with 1000 nodes
 	graph := createCircularGraph(10000)
@@ -70,3 +121,10 @@ func BenchmarkProofVerificationLargeGraph(b *testing.B) {
 		proof.Verify()
 	}
 }
+
+func TestBenchmarkGraphsAreColored(t *testing.T) {
+	for _, n := range []int{3, 10, 11, 100} {
+		assert.True(t, createCircularGraph(n).IsGraphColoringValid(), "cycle of %d nodes", n)
+	}
+	assert.True(t, createPlantedGraph(100, 500, 4).IsGraphColoringValid())
+}
@@ -0,0 +1,134 @@
+package coloringgraph
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ViolationKind tells what is wrong in a ColoringViolation.
+type ViolationKind uint8
+
+const (
+	// UnknownColor is a node whose color is not in Colors.
+	UnknownColor ViolationKind = iota + 1
+	// ConflictingEdge is an edge whose two ends have the same color.
+	ConflictingEdge
+	// DanglingEdge is an edge to a node that does not exist.
+	DanglingEdge
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case UnknownColor:
+		return "unknown color"
+	case ConflictingEdge:
+		return "conflicting edge"
+	case DanglingEdge:
+		return "dangling edge"
+	}
+	return fmt.Sprintf("ViolationKind(%d)", uint8(k))
+}
+
+// ColoringViolation is one reason a coloring is not valid.
+type ColoringViolation struct {
+	Kind ViolationKind
+
+	// Edge is the index of the edge in GetEdges, or -1 for an UnknownColor.
+	Edge int
+
+	// From and To are the ends of the edge. For an UnknownColor both are the
+	// node.
+	From int
+	To   int
+
+	// Color is the color of the node, or of both ends of a ConflictingEdge.
+	// It is empty for a DanglingEdge.
+	Color string
+}
+
+func (v ColoringViolation) String() string {
+	switch v.Kind {
+	case UnknownColor:
+		return fmt.Sprintf("node %d has unknown color %q", v.From, v.Color)
+	case ConflictingEdge:
+		return fmt.Sprintf("edge %d (%d, %d) has color %q on both ends", v.Edge, v.From, v.To, v.Color)
+	case DanglingEdge:
+		return fmt.Sprintf("edge %d (%d, %d) points to a missing node", v.Edge, v.From, v.To)
+	}
+	return fmt.Sprintf("%v at edge %d (%d, %d)", v.Kind, v.Edge, v.From, v.To)
+}
+
+// checkParallelThreshold is the number of nodes and edges above which Check
+// splits the work between goroutines.
+const checkParallelThreshold = 1 << 16
+
+// Check returns every violation of the coloring: the nodes whose color is not
+// in Colors, by node id, then the edges whose ends have the same color or do
+// not exist, by edge index. A valid coloring returns nil. With an empty Colors
+// set every node is an UnknownColor; zkp.Proofer.CheckWitness drops those
+// violations, so the proofer only requires the edges to be properly colored.
+//
+// Large graphs are checked by runtime.GOMAXPROCS(0) goroutines; the result
+// is the same.
+func (cg *ColoringGraph) Check() []ColoringViolation {
+	nodes, edges := cg.GetNodes(), cg.GetEdges()
+
+	checkNode := func(i int, out []ColoringViolation) []ColoringViolation {
+		color := string(nodes[i].Value)
+		if _, ok := cg.Colors[color]; !ok {
+			out = append(out, ColoringViolation{Kind: UnknownColor, Edge: -1, From: i, To: i, Color: color})
+		}
+		return out
+	}
+	checkEdge := func(i int, out []ColoringViolation) []ColoringViolation {
+		edge := edges[i]
+		if edge.From < 0 || edge.From >= len(nodes) || edge.To < 0 || edge.To >= len(nodes) {
+			return append(out, ColoringViolation{Kind: DanglingEdge, Edge: i, From: edge.From, To: edge.To})
+		}
+		if color := nodes[edge.From].Value; color == nodes[edge.To].Value {
+			out = append(out, ColoringViolation{Kind: ConflictingEdge, Edge: i, From: edge.From, To: edge.To, Color: string(color)})
+		}
+		return out
+	}
+
+	workers := 1
+	if len(nodes)+len(edges) > checkParallelThreshold {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	violations := checkRange(len(nodes), workers, checkNode)
+	return append(violations, checkRange(len(edges), workers, checkEdge)...)
+}
+
+// checkRange calls check for 0 to n-1, split into one chunk per worker, and
+// returns the violations in order.
+func checkRange(n, workers int, check func(i int, out []ColoringViolation) []ColoringViolation) []ColoringViolation {
+	if workers <= 1 || n < workers {
+		var out []ColoringViolation
+		for i := range n {
+			out = check(i, out)
+		}
+		return out
+	}
+
+	chunks := make([][]ColoringViolation, workers)
+	size := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w * size; i < min((w+1)*size, n); i++ {
+				chunks[w] = check(i, chunks[w])
+			}
+		}()
+	}
+	wg.Wait()
+
+	var out []ColoringViolation
+	for _, chunk := range chunks {
+		out = append(out, chunk...)
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package coloringgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		colors   []ColorNodeValue
+		edges    [][2]int
+		expected []ColoringViolation
+	}{
+		{
+			name:   "valid coloring",
+			colors: []ColorNodeValue{"red", "blue", "green"},
+			edges:  [][2]int{{0, 1}, {1, 2}, {2, 0}},
+		},
+		{
+			name:   "conflicting edge",
+			colors: []ColorNodeValue{"red", "blue", "red"},
+			edges:  [][2]int{{0, 1}, {1, 2}, {2, 0}},
+			expected: []ColoringViolation{
+				{Kind: ConflictingEdge, Edge: 2, From: 2, To: 0, Color: "red"},
+			},
+		},
+		{
+			name:   "unknown colors",
+			colors: []ColorNodeValue{"red", "purple", ""},
+			edges:  [][2]int{{0, 1}},
+			expected: []ColoringViolation{
+				{Kind: UnknownColor, Edge: -1, From: 1, To: 1, Color: "purple"},
+				{Kind: UnknownColor, Edge: -1, From: 2, To: 2, Color: ""},
+			},
+		},
+		{
+			name:   "self-loop",
+			colors: []ColorNodeValue{"red"},
+			edges:  [][2]int{{0, 0}},
+			expected: []ColoringViolation{
+				{Kind: ConflictingEdge, Edge: 0, From: 0, To: 0, Color: "red"},
+			},
+		},
+		{
+			name:   "dangling edge",
+			colors: []ColorNodeValue{"red", "blue"},
+			edges:  [][2]int{{0, 1}, {1, 5}},
+			expected: []ColoringViolation{
+				{Kind: DanglingEdge, Edge: 1, From: 1, To: 5},
+			},
+		},
+		{
+			name:   "nodes before edges",
+			colors: []ColorNodeValue{"red", "red", "pink"},
+			edges:  [][2]int{{0, 1}, {1, 2}},
+			expected: []ColoringViolation{
+				{Kind: UnknownColor, Edge: -1, From: 2, To: 2, Color: "pink"},
+				{Kind: ConflictingEdge, Edge: 0, From: 0, To: 1, Color: "red"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := NewColoringGraph()
+			cg.Colors = map[string]struct{}{"red": {}, "blue": {}, "green": {}}
+			for _, color := range tt.colors {
+				cg.AddNode(color)
+			}
+			for _, edge := range tt.edges {
+				cg.AddEdge(edge[0], edge[1])
+			}
+
+			violations := cg.Check()
+			if !reflect.DeepEqual(violations, tt.expected) {
+				t.Errorf("Check() = %v, want %v", violations, tt.expected)
+			}
+			if valid := cg.IsGraphColoringValid(); valid != (len(tt.expected) == 0) {
+				t.Errorf("IsGraphColoringValid() = %v with %d violations", valid, len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestCheckLargeGraph(t *testing.T) {
+	// A cycle large enough to be checked in parallel, colored with two colors
+	// so that the odd length makes one conflict, plus a few unknown colors
+	const n = checkParallelThreshold + 1
+	cg := NewColoringGraph()
+	cg.Colors = map[string]struct{}{"red": {}, "blue": {}}
+	for i := range n {
+		color := ColorNodeValue("red")
+		switch {
+		case i%10_000 == 5:
+			color = "green"
+		case i%2 == 1:
+			color = "blue"
+		}
+		cg.AddNode(color)
+	}
+	for i := range n {
+		cg.AddEdge(i, (i+1)%n)
+	}
+
+	violations := cg.Check()
+
+	var expected []ColoringViolation
+	for i := 5; i < n; i += 10_000 {
+		expected = append(expected, ColoringViolation{Kind: UnknownColor, Edge: -1, From: i, To: i, Color: "green"})
+	}
+	expected = append(expected, ColoringViolation{Kind: ConflictingEdge, Edge: n - 1, From: n - 1, To: 0, Color: "red"})
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Check() = %v, want %v", violations, expected)
+	}
+}
+
+func TestColoringViolationString(t *testing.T) {
+	tests := []struct {
+		violation ColoringViolation
+		expected  string
+	}{
+		{
+			violation: ColoringViolation{Kind: UnknownColor, Edge: -1, From: 3, To: 3, Color: "pink"},
+			expected:  `node 3 has unknown color "pink"`,
+		},
+		{
+			violation: ColoringViolation{Kind: ConflictingEdge, Edge: 1, From: 0, To: 2, Color: "red"},
+			expected:  `edge 1 (0, 2) has color "red" on both ends`,
+		},
+		{
+			violation: ColoringViolation{Kind: DanglingEdge, Edge: 4, From: 1, To: 9},
+			expected:  "edge 4 (1, 9) points to a missing node",
+		},
+	}
+
+	for _, tt := range tests {
+		if s := tt.violation.String(); s != tt.expected {
+			t.Errorf("String() = %q, want %q", s, tt.expected)
+		}
+	}
+}
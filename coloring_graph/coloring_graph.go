@@ -24,19 +24,28 @@ func (cg *ColoringGraph) Clone() *ColoringGraph {
 	}
 }
 
+// IsGraphColoringValid reports whether every node has a color in Colors and
+// the two ends of every edge have different colors. Use Check to find out
+// what is wrong with an invalid coloring.
+//
+// A graph with an empty Colors set is never valid here, but the proofer does
+// not check colors against an empty set: zkp.Proofer.CheckWitness and Prove
+// accept such a graph when its edges are properly colored.
 func (cg *ColoringGraph) IsGraphColoringValid() bool {
-	for _, node := range cg.GetNodes() {
+	nodes := cg.GetNodes()
+	for _, node := range nodes {
 		if _, ok := cg.Colors[string(node.Value)]; !ok {
 			return false
 		}
 	}
 
 	for _, edge := range cg.GetEdges() {
-		node1 := cg.GetNodes()[edge.From]
-		node2 := cg.GetNodes()[edge.To]
+		if edge.From < 0 || edge.From >= len(nodes) || edge.To < 0 || edge.To >= len(nodes) {
+			return false
+		}
 
 		// Check if nodes have the same color
-		if node1.Value == node2.Value {
+		if nodes[edge.From].Value == nodes[edge.To].Value {
 			return false
 		}
 	}
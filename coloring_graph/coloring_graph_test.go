@@ -265,3 +265,41 @@ func TestColoringGraphSerialization(t *testing.T) {
 		})
 	}
 }
+
+func TestColoringGraphMutation(t *testing.T) {
+	cg := NewColoringGraph()
+	cg.Colors = map[string]struct{}{"red": {}, "blue": {}}
+	cg.AddNode("red")
+	cg.AddNode("blue")
+	cg.AddNode("red")
+	cg.AddEdge(0, 1)
+	cg.AddEdge(1, 2)
+	cg.AddEdge(0, 2)
+
+	if cg.IsGraphColoringValid() {
+		t.Fatalf("coloring with a conflicting edge is valid")
+	}
+
+	if err := cg.RemoveEdge(0, 2); err != nil {
+		t.Fatalf("RemoveEdge(0, 2) failed: %v", err)
+	}
+	if !cg.IsGraphColoringValid() {
+		t.Errorf("coloring is invalid after removing the conflicting edge")
+	}
+
+	if _, err := cg.RemoveNode(1); err != nil {
+		t.Fatalf("RemoveNode(1) failed: %v", err)
+	}
+	if err := cg.ReplaceNodeValue(1, "blue"); err != nil {
+		t.Fatalf("ReplaceNodeValue(1) failed: %v", err)
+	}
+	cg.AddEdge(0, 1)
+
+	if !cg.IsGraphColoringValid() {
+		t.Errorf("coloring is invalid after the mutations")
+	}
+	cg.ShuffleColors()
+	if !cg.IsGraphColoringValid() {
+		t.Errorf("coloring is invalid after shuffling the mutated graph")
+	}
+}
@@ -1,26 +1,47 @@
 package coloringgraph
 
-import "math/rand/v2"
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+)
 
+// ShuffleColors randomly permutes the colors of the graph using crypto/rand.
 func (cg *ColoringGraph) ShuffleColors() {
+	if err := cg.ShuffleColorsWithReader(rand.Reader); err != nil {
+		panic(err)
+	}
+}
+
+// ShuffleColorsWithReader randomly permutes the colors of the graph using the
+// entropy read from r. The colors are sorted before they are shuffled, so the
+// permutation only depends on the bytes read from r.
+func (cg *ColoringGraph) ShuffleColorsWithReader(r io.Reader) error {
 	// Get all unique colors from nodes
 	colorsMap := make(map[string]struct{})
 	for _, node := range cg.GetNodes() {
 		colorsMap[string(node.Value)] = struct{}{}
 	}
 
-	// Convert colors map to slice
+	// Convert colors map to a sorted slice
 	colorsSlice := make([]string, 0, len(colorsMap))
 	for color := range colorsMap {
 		colorsSlice = append(colorsSlice, color)
 	}
+	slices.Sort(colorsSlice)
 
-	// Create a new shuffled slice
+	// Create a new shuffled slice (Fisher-Yates)
 	shuffledSlice := make([]string, len(colorsSlice))
 	copy(shuffledSlice, colorsSlice)
-	rand.Shuffle(len(shuffledSlice), func(i, j int) {
+	for i := len(shuffledSlice) - 1; i > 0; i-- {
+		j, err := randomIndex(r, uint64(i+1))
+		if err != nil {
+			return err
+		}
 		shuffledSlice[i], shuffledSlice[j] = shuffledSlice[j], shuffledSlice[i]
-	})
+	}
 
 	// Create mapping from original colors to shuffled colors
 	shuffleMap := make(map[string]string)
@@ -32,4 +53,23 @@ func (cg *ColoringGraph) ShuffleColors() {
 	for _, node := range cg.GetNodes() {
 		node.Value = ColorNodeValue(shuffleMap[string(node.Value)])
 	}
+	return nil
+}
+
+// randomIndex reads a uniformly distributed index in [0, n) from r.
+// Values that would bias the result towards small indices are rejected.
+func randomIndex(r io.Reader, n uint64) (uint64, error) {
+	// The largest multiple of n that fits in a uint64
+	limit := math.MaxUint64 - math.MaxUint64%n
+
+	buf := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return 0, err
+		}
+		value := binary.BigEndian.Uint64(buf)
+		if value < limit {
+			return value % n, nil
+		}
+	}
 }
@@ -1,6 +1,10 @@
 package coloringgraph
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
 	"testing"
 )
 
@@ -74,3 +78,63 @@ func TestShuffleColors(t *testing.T) {
 		}
 	}
 }
+
+func TestShuffleColorsWithReaderDeterministic(t *testing.T) {
+	build := func() *ColoringGraph {
+		cg := NewColoringGraph()
+		for _, color := range []string{"red", "blue", "green", "yellow", "purple", "red", "blue"} {
+			cg.AddNode(ColorNodeValue(color))
+		}
+		return cg
+	}
+
+	var seed [32]byte
+	seed[0] = 7
+
+	first := build()
+	if err := first.ShuffleColorsWithReader(rand.NewChaCha8(seed)); err != nil {
+		t.Fatalf("shuffle failed: %v", err)
+	}
+	second := build()
+	if err := second.ShuffleColorsWithReader(rand.NewChaCha8(seed)); err != nil {
+		t.Fatalf("shuffle failed: %v", err)
+	}
+
+	for i, node := range first.GetNodes() {
+		if node.Value != second.GetNodes()[i].Value {
+			t.Errorf("node %d: %s != %s, same entropy should give the same permutation", i, node.Value, second.GetNodes()[i].Value)
+		}
+	}
+
+	// Nodes that shared a color still share one
+	nodes := first.GetNodes()
+	if nodes[0].Value != nodes[5].Value || nodes[1].Value != nodes[6].Value {
+		t.Error("shuffle should map equal colors to equal colors")
+	}
+}
+
+func TestShuffleColorsWithReaderError(t *testing.T) {
+	cg := NewColoringGraph()
+	cg.AddNode(ColorNodeValue("red"))
+	cg.AddNode(ColorNodeValue("blue"))
+
+	err := cg.ShuffleColorsWithReader(bytes.NewReader([]byte{1, 2, 3}))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestRandomIndex(t *testing.T) {
+	// The first value is above the rejection limit for n = 3 and is skipped
+	data := []byte{
+		255, 255, 255, 255, 255, 255, 255, 255,
+		0, 0, 0, 0, 0, 0, 0, 5,
+	}
+	index, err := randomIndex(bytes.NewReader(data), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if index != 2 {
+		t.Errorf("randomIndex = %d, want 2", index)
+	}
+}
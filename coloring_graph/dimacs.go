@@ -0,0 +1,219 @@
+package coloringgraph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+var ErrInvalidDIMACS = errors.New("invalid DIMACS data")
+
+// DefaultDIMACSMaxNodes is the node limit of ReadDIMACS. The largest standard
+// benchmarks have a few thousand vertices.
+const DefaultDIMACSMaxNodes = 1 << 20
+
+// DIMACSOptions limits the size of a graph read by ReadDIMACSWithOptions.
+// A zero field means no limit.
+type DIMACSOptions struct {
+	MaxNodes int
+	MaxEdges int
+}
+
+// ReadDIMACS reads a graph in the DIMACS .col format:
+//
+//	c comment
+//	p edge <nodes> <edges>
+//	e <from> <to>
+//
+// Vertices are numbered from 1 in the file and from 0 in the graph. Comments,
+// blank lines and other line types (such as node weights) are skipped. The
+// edge count of the problem line is not checked because many published
+// instances list each edge in both directions; use Graph.Normalize to remove
+// the repetitions. The nodes have no color, see ReadDIMACSSolution.
+//
+// The nodes are allocated from the problem line, so ReadDIMACS rejects graphs
+// with more than DefaultDIMACSMaxNodes nodes. Use ReadDIMACSWithOptions to
+// change the limit.
+func ReadDIMACS(r io.Reader) (*ColoringGraph, error) {
+	return ReadDIMACSWithOptions(r, DIMACSOptions{MaxNodes: DefaultDIMACSMaxNodes})
+}
+
+// ReadDIMACSWithOptions is like ReadDIMACS but with the given limits. A graph
+// above a limit is rejected with an error wrapping both ErrInvalidDIMACS and
+// graph.ErrLimitExceeded.
+func ReadDIMACSWithOptions(r io.Reader, opts DIMACSOptions) (*ColoringGraph, error) {
+	cg := NewColoringGraph()
+	nodes := -1
+	edges := 0
+
+	err := scanDIMACS(r, func(line int, fields []string) error {
+		switch fields[0] {
+		case "p":
+			if nodes >= 0 {
+				return dimacsErrorf(line, "second problem line")
+			}
+			if len(fields) != 4 || (fields[1] != "edge" && fields[1] != "col") {
+				return dimacsErrorf(line, "problem line must be \"p edge <nodes> <edges>\"")
+			}
+			n, err := parseDIMACSCount(line, fields[2])
+			if err != nil {
+				return err
+			}
+			if _, err := parseDIMACSCount(line, fields[3]); err != nil {
+				return err
+			}
+			if opts.MaxNodes > 0 && n > opts.MaxNodes {
+				return dimacsLimitErrorf(line, "%d nodes above %d", n, opts.MaxNodes)
+			}
+
+			nodes = n
+			for range nodes {
+				cg.AddNode(ColorNodeValue(""))
+			}
+
+		case "e":
+			if nodes < 0 {
+				return dimacsErrorf(line, "edge before the problem line")
+			}
+			if len(fields) != 3 {
+				return dimacsErrorf(line, "edge line must be \"e <from> <to>\"")
+			}
+			from, err := parseDIMACSVertex(line, fields[1], nodes)
+			if err != nil {
+				return err
+			}
+			to, err := parseDIMACSVertex(line, fields[2], nodes)
+			if err != nil {
+				return err
+			}
+			if edges++; opts.MaxEdges > 0 && edges > opts.MaxEdges {
+				return dimacsLimitErrorf(line, "more than %d edges", opts.MaxEdges)
+			}
+			cg.AddEdge(from, to)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if nodes < 0 {
+		return nil, fmt.Errorf("%w: no problem line", ErrInvalidDIMACS)
+	}
+	return cg, nil
+}
+
+// WriteDIMACS writes the structure of the graph in the DIMACS .col format,
+// numbering vertices from 1. Node colors are not written.
+func WriteDIMACS(w io.Writer, cg *ColoringGraph) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "p edge %d %d\n", len(cg.GetNodes()), len(cg.GetEdges()))
+	for _, edge := range cg.GetEdges() {
+		fmt.Fprintf(bw, "e %d %d\n", edge.From+1, edge.To+1)
+	}
+
+	return bw.Flush()
+}
+
+// ReadDIMACSSolution reads a coloring of cg and sets it on the nodes. The
+// colors are added to cg.Colors. Each line gives the color of one vertex,
+// numbered from 1:
+//
+//	c comment
+//	s col <colors>
+//	l <vertex> <color>
+//
+// The "l" prefix is optional, so plain "<vertex> <color>" lines are read too.
+// Every vertex must get exactly one color.
+func ReadDIMACSSolution(r io.Reader, cg *ColoringGraph) error {
+	nodes := len(cg.GetNodes())
+	colors := make([]string, nodes)
+
+	err := scanDIMACS(r, func(line int, fields []string) error {
+		switch fields[0] {
+		case "s":
+			return nil
+		case "l":
+			fields = fields[1:]
+		}
+
+		if len(fields) != 2 {
+			return dimacsErrorf(line, "color line must be \"l <vertex> <color>\"")
+		}
+		vertex, err := parseDIMACSVertex(line, fields[0], nodes)
+		if err != nil {
+			return err
+		}
+		if colors[vertex] != "" {
+			return dimacsErrorf(line, "vertex %s has two colors", fields[0])
+		}
+		colors[vertex] = fields[1]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, color := range colors {
+		if color == "" {
+			return fmt.Errorf("%w: vertex %d has no color", ErrInvalidDIMACS, i+1)
+		}
+	}
+
+	for i, color := range colors {
+		cg.ReplaceNodeValue(i, ColorNodeValue(color))
+		cg.Colors[color] = struct{}{}
+	}
+	return nil
+}
+
+// scanDIMACS calls fn with the fields of every line that is not blank or a
+// comment, along with the line number.
+func scanDIMACS(r io.Reader, fn func(line int, fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		if err := fn(line, fields); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read DIMACS data: %w", err)
+	}
+	return nil
+}
+
+func parseDIMACSCount(line int, field string) (int, error) {
+	n, err := strconv.Atoi(field)
+	if err != nil || n < 0 {
+		return 0, dimacsErrorf(line, "invalid count %q", field)
+	}
+	return n, nil
+}
+
+// parseDIMACSVertex parses a 1-based vertex number into a 0-based node index.
+func parseDIMACSVertex(line int, field string, nodes int) (int, error) {
+	v, err := strconv.Atoi(field)
+	if err != nil || v < 1 || v > nodes {
+		return 0, dimacsErrorf(line, "invalid vertex %q with %d vertices", field, nodes)
+	}
+	return v - 1, nil
+}
+
+func dimacsErrorf(line int, format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidDIMACS, line, fmt.Sprintf(format, args...))
+}
+
+func dimacsLimitErrorf(line int, format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %w: %s", ErrInvalidDIMACS, line, graph.ErrLimitExceeded, fmt.Sprintf(format, args...))
+}
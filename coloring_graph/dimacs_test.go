@@ -0,0 +1,161 @@
+package coloringgraph
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+const triangleDIMACS = `c FILE: triangle.col
+c a triangle with a pendant vertex
+
+p edge 4 4
+e 1 2
+e 2 3
+e 3 1
+e 3 4
+`
+
+func TestReadDIMACS(t *testing.T) {
+	cg, err := ReadDIMACS(strings.NewReader(triangleDIMACS))
+	if err != nil {
+		t.Fatalf("ReadDIMACS failed: %v", err)
+	}
+
+	if nodes := len(cg.GetNodes()); nodes != 4 {
+		t.Errorf("read %d nodes, want 4", nodes)
+	}
+	expected := []graph.Edge{{From: 0, To: 1}, {From: 1, To: 2}, {From: 2, To: 0}, {From: 2, To: 3}}
+	edges := cg.GetEdges()
+	if len(edges) != len(expected) {
+		t.Fatalf("read %d edges, want %d", len(edges), len(expected))
+	}
+	for i, edge := range edges {
+		if edge != expected[i] {
+			t.Errorf("edge %d = %v, want %v", i, edge, expected[i])
+		}
+	}
+}
+
+func TestReadDIMACSErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "no problem line", data: "c only comments\n"},
+		{name: "edge before problem line", data: "e 1 2\np edge 2 1\n"},
+		{name: "two problem lines", data: "p edge 2 1\np edge 2 1\n"},
+		{name: "unknown problem type", data: "p cnf 2 1\n"},
+		{name: "invalid node count", data: "p edge two 1\n"},
+		{name: "zero vertex", data: "p edge 2 1\ne 0 1\n"},
+		{name: "vertex out of range", data: "p edge 2 1\ne 1 3\n"},
+		{name: "short edge line", data: "p edge 2 1\ne 1\n"},
+		{name: "too many nodes", data: "p edge 4000000000 0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadDIMACS(strings.NewReader(tt.data)); !errors.Is(err, ErrInvalidDIMACS) {
+				t.Errorf("ReadDIMACS error = %v, want ErrInvalidDIMACS", err)
+			}
+		})
+	}
+}
+
+func TestWriteDIMACSRoundTrip(t *testing.T) {
+	cg, err := ReadDIMACS(strings.NewReader(triangleDIMACS))
+	if err != nil {
+		t.Fatalf("ReadDIMACS failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDIMACS(&buf, cg); err != nil {
+		t.Fatalf("WriteDIMACS failed: %v", err)
+	}
+	if expected := "p edge 4 4\ne 1 2\ne 2 3\ne 3 1\ne 3 4\n"; buf.String() != expected {
+		t.Errorf("WriteDIMACS wrote %q, want %q", buf.String(), expected)
+	}
+
+	again, err := ReadDIMACS(&buf)
+	if err != nil {
+		t.Fatalf("ReadDIMACS of the written graph failed: %v", err)
+	}
+	if again.Fingerprint() != cg.Fingerprint() {
+		t.Errorf("round trip changed the graph")
+	}
+}
+
+func TestReadDIMACSWithOptions(t *testing.T) {
+	opts := DIMACSOptions{MaxNodes: 4, MaxEdges: 4}
+	if _, err := ReadDIMACSWithOptions(strings.NewReader(triangleDIMACS), opts); err != nil {
+		t.Fatalf("ReadDIMACSWithOptions failed: %v", err)
+	}
+
+	opts.MaxNodes = 3
+	if _, err := ReadDIMACSWithOptions(strings.NewReader(triangleDIMACS), opts); !errors.Is(err, graph.ErrLimitExceeded) || !errors.Is(err, ErrInvalidDIMACS) {
+		t.Errorf("ReadDIMACSWithOptions error = %v, want ErrLimitExceeded", err)
+	}
+
+	opts = DIMACSOptions{MaxEdges: 3}
+	if _, err := ReadDIMACSWithOptions(strings.NewReader(triangleDIMACS), opts); !errors.Is(err, graph.ErrLimitExceeded) {
+		t.Errorf("ReadDIMACSWithOptions error = %v, want ErrLimitExceeded", err)
+	}
+}
+
+func TestReadDIMACSSolution(t *testing.T) {
+	cg, err := ReadDIMACS(strings.NewReader(triangleDIMACS))
+	if err != nil {
+		t.Fatalf("ReadDIMACS failed: %v", err)
+	}
+
+	solution := "c a 3-coloring\ns col 3\nl 1 1\nl 2 2\n3 3\nl 4 1\n"
+	if err := ReadDIMACSSolution(strings.NewReader(solution), cg); err != nil {
+		t.Fatalf("ReadDIMACSSolution failed: %v", err)
+	}
+
+	for i, expected := range []ColorNodeValue{"1", "2", "3", "1"} {
+		if value := cg.GetNodes()[i].Value; value != expected {
+			t.Errorf("node %d color = %q, want %q", i, value, expected)
+		}
+	}
+	if len(cg.Colors) != 3 {
+		t.Errorf("graph has %d colors, want 3", len(cg.Colors))
+	}
+	if !cg.IsGraphColoringValid() {
+		t.Errorf("coloring read from the solution is not valid")
+	}
+}
+
+func TestReadDIMACSSolutionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "missing vertex", data: "l 1 1\nl 2 2\nl 3 3\n"},
+		{name: "two colors", data: "l 1 1\nl 1 2\nl 2 2\nl 3 3\nl 4 1\n"},
+		{name: "vertex out of range", data: "l 5 1\n"},
+		{name: "short line", data: "l 1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg, err := ReadDIMACS(strings.NewReader(triangleDIMACS))
+			if err != nil {
+				t.Fatalf("ReadDIMACS failed: %v", err)
+			}
+
+			if err := ReadDIMACSSolution(strings.NewReader(tt.data), cg); !errors.Is(err, ErrInvalidDIMACS) {
+				t.Errorf("ReadDIMACSSolution error = %v, want ErrInvalidDIMACS", err)
+			}
+			for i, node := range cg.GetNodes() {
+				if node.Value != "" {
+					t.Errorf("node %d got color %q from a rejected solution", i, node.Value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package coloringgraph
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+// NodeAttributes is a graph.WriteDOT and graph.WriteGraphML hook that labels
+// every node with its color and fills it. Colors that are Graphviz color names
+// or "#rrggbb" values are used as they are, other colors (such as "1", "2")
+// get "#rrggbb" values with evenly spaced hues. Nodes without a color are
+// labeled with their id.
+func (cg *ColoringGraph) NodeAttributes() graph.NodeAttributes[ColorNodeValue] {
+	used := make(map[string]struct{}, len(cg.Colors))
+	maps.Copy(used, cg.Colors)
+	for _, node := range cg.GetNodes() {
+		used[string(node.Value)] = struct{}{}
+	}
+	maps.DeleteFunc(used, func(color string, _ struct{}) bool {
+		return color == "" || isNamedColor(color)
+	})
+
+	colors := slices.Sorted(maps.Keys(used))
+	hues := make(map[string]string, len(colors))
+	for i, color := range colors {
+		hues[color] = hueColor(float64(i) / float64(len(colors)))
+	}
+
+	return func(node *graph.Node[ColorNodeValue]) map[string]string {
+		color := string(node.Value)
+		if color == "" {
+			return map[string]string{"label": strconv.Itoa(node.Id)}
+		}
+
+		fill, ok := hues[color]
+		if !ok {
+			fill = color
+		}
+		return map[string]string{"label": color, "style": "filled", "fillcolor": fill}
+	}
+}
+
+// isNamedColor reports whether Graphviz can use the color as it is: a name
+// made of letters or a "#rrggbb" value.
+func isNamedColor(color string) bool {
+	if len(color) == 7 && color[0] == '#' {
+		_, err := strconv.ParseUint(color[1:], 16, 32)
+		return err == nil
+	}
+
+	for _, c := range color {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// hueColor returns a "#rrggbb" color with the given hue in [0, 1), and a fixed
+// saturation and value that keep black labels readable.
+func hueColor(hue float64) string {
+	const saturation, value = 0.6, 0.95
+
+	sector := hue * 6
+	f := sector - math.Floor(sector)
+	p := value * (1 - saturation)
+	q := value * (1 - saturation*f)
+	t := value * (1 - saturation*(1-f))
+
+	var r, g, b float64
+	switch int(sector) % 6 {
+	case 0:
+		r, g, b = value, t, p
+	case 1:
+		r, g, b = q, value, p
+	case 2:
+		r, g, b = p, value, t
+	case 3:
+		r, g, b = p, q, value
+	case 4:
+		r, g, b = t, p, value
+	default:
+		r, g, b = value, p, q
+	}
+	return fmt.Sprintf("#%02x%02x%02x", int(math.Round(r*255)), int(math.Round(g*255)), int(math.Round(b*255)))
+}
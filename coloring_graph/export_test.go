@@ -0,0 +1,80 @@
+package coloringgraph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+func TestNodeAttributes(t *testing.T) {
+	cg := NewColoringGraph()
+	cg.Colors = map[string]struct{}{"1": {}, "2": {}, "3": {}}
+	cg.AddNode("1")
+	cg.AddNode("red")
+	cg.AddNode("#00ff00")
+	cg.AddNode("2")
+	cg.AddNode("")
+
+	attrs := cg.NodeAttributes()
+	tests := []struct {
+		name     string
+		node     int
+		expected map[string]string
+	}{
+		{name: "numbered color", node: 0, expected: map[string]string{"label": "1", "style": "filled", "fillcolor": "#f26161"}},
+		{name: "color name", node: 1, expected: map[string]string{"label": "red", "style": "filled", "fillcolor": "red"}},
+		{name: "hex color", node: 2, expected: map[string]string{"label": "#00ff00", "style": "filled", "fillcolor": "#00ff00"}},
+		{name: "no color", node: 4, expected: map[string]string{"label": "4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := attrs(cg.GetNodes()[tt.node])
+			if len(got) != len(tt.expected) {
+				t.Fatalf("attributes = %v, want %v", got, tt.expected)
+			}
+			for name, value := range tt.expected {
+				if got[name] != value {
+					t.Errorf("attribute %q = %q, want %q", name, got[name], value)
+				}
+			}
+		})
+	}
+
+	// Different colors get different fills
+	if attrs(cg.GetNodes()[0])["fillcolor"] == attrs(cg.GetNodes()[3])["fillcolor"] {
+		t.Errorf("colors 1 and 2 have the same fill")
+	}
+}
+
+func TestReadGraphML(t *testing.T) {
+	cg := NewColoringGraph()
+	cg.Colors = map[string]struct{}{"red": {}, "blue": {}}
+	cg.AddNode("red")
+	cg.AddNode("blue")
+	cg.AddNode("red")
+	cg.AddEdge(0, 1)
+	cg.AddEdge(1, 2)
+
+	var buf bytes.Buffer
+	if err := graph.WriteGraphML(&buf, cg.Graph, cg.NodeAttributes()); err != nil {
+		t.Fatalf("WriteGraphML failed: %v", err)
+	}
+
+	read, err := ReadGraphML(&buf, "label")
+	if err != nil {
+		t.Fatalf("ReadGraphML failed: %v", err)
+	}
+	for i, node := range read.GetNodes() {
+		if node.Value != cg.GetNodes()[i].Value {
+			t.Errorf("node %d color = %q, want %q", i, node.Value, cg.GetNodes()[i].Value)
+		}
+	}
+	if len(read.Colors) != 2 {
+		t.Errorf("read %d colors, want 2", len(read.Colors))
+	}
+	if !read.IsGraphColoringValid() {
+		t.Errorf("coloring read from GraphML is not valid")
+	}
+}
@@ -0,0 +1,28 @@
+package coloringgraph
+
+import (
+	"io"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+// ReadGraphML reads a graph with graph.ReadGraphML and colors every node with
+// its colorAttr attribute, adding the colors to Colors. Nodes without the
+// attribute have no color. Use "fillcolor" for the fill colors set in yEd or
+// Gephi.
+func ReadGraphML(r io.Reader, colorAttr string) (*ColoringGraph, error) {
+	g, err := graph.ReadGraphML(r, func(attrs map[string]string) (ColorNodeValue, error) {
+		return ColorNodeValue(attrs[colorAttr]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cg := &ColoringGraph{Graph: g, Colors: make(map[string]struct{})}
+	for _, node := range g.GetNodes() {
+		if node.Value != "" {
+			cg.Colors[string(node.Value)] = struct{}{}
+		}
+	}
+	return cg, nil
+}
@@ -0,0 +1,36 @@
+package coloringgraph
+
+import (
+	"io"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+// ReadNodeLink reads a networkx node-link graph with graph.ReadNodeLink and
+// colors every node with its colorAttr attribute, adding the colors to Colors.
+// Nodes without the attribute have no color. Numeric colors, such as the ones
+// networkx.greedy_color assigns, are read as their decimal form.
+func ReadNodeLink(r io.Reader, colorAttr string) (*ColoringGraph, error) {
+	g, err := graph.ReadNodeLink(r, func(attrs map[string]string) (ColorNodeValue, error) {
+		return ColorNodeValue(attrs[colorAttr]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cg := &ColoringGraph{Graph: g, Colors: make(map[string]struct{})}
+	for _, node := range g.GetNodes() {
+		if node.Value != "" {
+			cg.Colors[string(node.Value)] = struct{}{}
+		}
+	}
+	return cg, nil
+}
+
+// WriteNodeLink writes the graph with graph.WriteNodeLink, storing the color of
+// every node in its colorAttr attribute.
+func WriteNodeLink(w io.Writer, cg *ColoringGraph, colorAttr string) error {
+	return graph.WriteNodeLink(w, cg.Graph, func(node *graph.Node[ColorNodeValue]) map[string]string {
+		return map[string]string{colorAttr: string(node.Value)}
+	})
+}
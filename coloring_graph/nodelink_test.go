@@ -0,0 +1,59 @@
+package coloringgraph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadNodeLink(t *testing.T) {
+	// networkx.node_link_data of a path colored with networkx.greedy_color
+	data := `{"directed": false, "multigraph": false, "graph": {},
+		"nodes": [{"color": 0, "id": "x"}, {"color": 1, "id": "y"}, {"color": 0, "id": "z"}, {"id": "w"}],
+		"edges": [{"source": "x", "target": "y"}, {"source": "y", "target": "z"}]}`
+
+	cg, err := ReadNodeLink(strings.NewReader(data), "color")
+	if err != nil {
+		t.Fatalf("ReadNodeLink failed: %v", err)
+	}
+
+	for i, expected := range []ColorNodeValue{"0", "1", "0", ""} {
+		if value := cg.GetNodes()[i].Value; value != expected {
+			t.Errorf("node %d color = %q, want %q", i, value, expected)
+		}
+	}
+	if len(cg.Colors) != 2 {
+		t.Errorf("read %d colors, want 2", len(cg.Colors))
+	}
+}
+
+func TestWriteNodeLinkRoundTrip(t *testing.T) {
+	cg := NewColoringGraph()
+	cg.Colors = map[string]struct{}{"red": {}, "blue": {}}
+	cg.AddNode("red")
+	cg.AddNode("blue")
+	cg.AddNode("red")
+	cg.AddEdge(0, 1)
+	cg.AddEdge(1, 2)
+
+	var buf bytes.Buffer
+	if err := WriteNodeLink(&buf, cg, "color"); err != nil {
+		t.Fatalf("WriteNodeLink failed: %v", err)
+	}
+
+	read, err := ReadNodeLink(&buf, "color")
+	if err != nil {
+		t.Fatalf("ReadNodeLink failed: %v", err)
+	}
+	if read.Fingerprint() != cg.Fingerprint() {
+		t.Errorf("round trip changed the graph structure")
+	}
+	for i, node := range read.GetNodes() {
+		if node.Value != cg.GetNodes()[i].Value {
+			t.Errorf("node %d color = %q, want %q", i, node.Value, cg.GetNodes()[i].Value)
+		}
+	}
+	if !read.IsGraphColoringValid() {
+		t.Errorf("coloring read back is not valid")
+	}
+}
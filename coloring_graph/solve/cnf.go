@@ -0,0 +1,134 @@
+package solve
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+// CNF is a boolean formula in conjunctive normal form. Variables are numbered
+// from 1 and a literal is a variable or its negation, written as a negative
+// number, like in the DIMACS CNF format.
+type CNF struct {
+	Vars    int
+	Clauses [][]int
+}
+
+// ColorVar returns the variable of EncodeColoring that is true when node v has
+// color c, for colors numbered from 0.
+func ColorVar(v, c, k int) int {
+	return v*k + c + 1
+}
+
+// EncodeColoring returns a formula that is satisfiable exactly when g has a
+// coloring with k colors:
+//
+//   - every node has at least one color,
+//   - no node has two colors,
+//   - the two ends of an edge do not have the same color.
+//
+// To cut the colorings that only differ by a permutation of the colors, the
+// nodes of a clique found greedily get fixed colors. Edges to missing nodes
+// are ignored; use Graph.Validate to find them.
+func EncodeColoring[T graph.NodeValue](g *graph.Graph[T], k int) *CNF {
+	n := len(g.GetNodes())
+	cnf := &CNF{Vars: n * k}
+
+	for v := range n {
+		atLeastOne := make([]int, k)
+		for c := range k {
+			atLeastOne[c] = ColorVar(v, c, k)
+		}
+		cnf.Clauses = append(cnf.Clauses, atLeastOne)
+
+		for c := range k {
+			for d := c + 1; d < k; d++ {
+				cnf.Clauses = append(cnf.Clauses, []int{-ColorVar(v, c, k), -ColorVar(v, d, k)})
+			}
+		}
+	}
+
+	neighbors := make([][]int, n)
+	for _, edge := range g.GetEdges() {
+		if edge.From < 0 || edge.From >= n || edge.To < 0 || edge.To >= n {
+			continue
+		}
+		neighbors[edge.From] = append(neighbors[edge.From], edge.To)
+		neighbors[edge.To] = append(neighbors[edge.To], edge.From)
+	}
+
+	for _, edge := range g.GetEdges() {
+		if edge.From < 0 || edge.From >= n || edge.To < 0 || edge.To >= n {
+			continue
+		}
+		for c := range k {
+			if edge.From == edge.To {
+				cnf.Clauses = append(cnf.Clauses, []int{-ColorVar(edge.From, c, k)})
+				continue
+			}
+			cnf.Clauses = append(cnf.Clauses, []int{-ColorVar(edge.From, c, k), -ColorVar(edge.To, c, k)})
+		}
+	}
+
+	// The nodes of a clique all have different colors, so any coloring can be
+	// permuted to give them the first colors in order
+	for c, v := range greedyClique(neighbors) {
+		if c >= k {
+			// The clique is larger than k, no coloring exists
+			cnf.Clauses = append(cnf.Clauses, []int{})
+			break
+		}
+		cnf.Clauses = append(cnf.Clauses, []int{ColorVar(v, c, k)})
+	}
+
+	return cnf
+}
+
+// greedyClique returns a clique built by adding nodes by decreasing degree.
+func greedyClique(neighbors [][]int) []int {
+	order := make([]int, len(neighbors))
+	for v := range order {
+		order[v] = v
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(len(neighbors[b]), len(neighbors[a]))
+	})
+
+	var clique []int
+	for _, v := range order {
+		if slices.Contains(neighbors[v], v) {
+			continue
+		}
+		adjacent := true
+		for _, u := range clique {
+			if !slices.Contains(neighbors[v], u) {
+				adjacent = false
+				break
+			}
+		}
+		if adjacent {
+			clique = append(clique, v)
+		}
+	}
+	return clique
+}
+
+// WriteDIMACS writes the formula in the DIMACS CNF format, to run it through
+// an external SAT solver.
+func (cnf *CNF) WriteDIMACS(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "p cnf %d %d\n", cnf.Vars, len(cnf.Clauses))
+	for _, clause := range cnf.Clauses {
+		for _, lit := range clause {
+			fmt.Fprintf(bw, "%d ", lit)
+		}
+		fmt.Fprintln(bw, "0")
+	}
+
+	return bw.Flush()
+}
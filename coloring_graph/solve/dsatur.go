@@ -0,0 +1,97 @@
+package solve
+
+import (
+	"fmt"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+)
+
+// DSatur colors next the node whose neighbors have the most distinct colors,
+// ties broken by degree, and gives it the first color none of its neighbors
+// has. It usually needs fewer colors than Greedy.
+func DSatur(cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+	p, err := newProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make([]int, len(p.neighbors))
+	for v := range colors {
+		colors[v] = uncolored
+	}
+	if err := p.dsaturFill(colors); err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+// DSaturRepair keeps the conflict-free part of the coloring of cg and colors
+// the other nodes like DSatur.
+func DSaturRepair(cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+	p, colors, err := newRepairProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	p.dropConflicts(colors)
+	if err := p.dsaturFill(colors); err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+// dsaturFill colors the uncolored nodes in DSatur order.
+func (p *problem) dsaturFill(colors []int) error {
+	k := len(p.palette)
+
+	// neighborColors[v*k+c] counts the neighbors of v colored c, and
+	// saturation[v] the distinct colors among them
+	neighborColors := make([]int, len(colors)*k)
+	saturation := make([]int, len(colors))
+	left := 0
+	for v, c := range colors {
+		if c == uncolored {
+			left++
+			continue
+		}
+		for _, u := range p.neighbors[v] {
+			if neighborColors[u*k+c] == 0 {
+				saturation[u]++
+			}
+			neighborColors[u*k+c]++
+		}
+	}
+
+	for ; left > 0; left-- {
+		v := uncolored
+		for u, c := range colors {
+			if c != uncolored {
+				continue
+			}
+			if v == uncolored || saturation[u] > saturation[v] ||
+				(saturation[u] == saturation[v] && len(p.neighbors[u]) > len(p.neighbors[v])) {
+				v = u
+			}
+		}
+
+		c := uncolored
+		for candidate := range k {
+			if neighborColors[v*k+candidate] == 0 {
+				c = candidate
+				break
+			}
+		}
+		if c == uncolored {
+			return fmt.Errorf("%w: node %d has neighbors of all %d colors", ErrNoColoring, v, k)
+		}
+
+		colors[v] = c
+		for _, u := range p.neighbors[v] {
+			if neighborColors[u*k+c] == 0 {
+				saturation[u]++
+			}
+			neighborColors[u*k+c]++
+		}
+	}
+	return nil
+}
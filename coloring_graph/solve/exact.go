@@ -0,0 +1,207 @@
+package solve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+)
+
+// ErrUnsatisfiable is returned by the exact solvers when the graph has no
+// coloring with k colors.
+var ErrUnsatisfiable = errors.New("no coloring exists")
+
+// BacktrackingMaxNodes is the largest graph Exact solves by backtracking
+// instead of with the SAT solver.
+const BacktrackingMaxNodes = 32
+
+// backtrackCheckInterval is the number of nodes the backtracking search
+// colors between two checks of the context.
+const backtrackCheckInterval = 1024
+
+// Exact finds a coloring with k colors or proves that none exists, in which
+// case it returns ErrUnsatisfiable. It first tries DSatur, then searches by
+// backtracking for graphs of up to BacktrackingMaxNodes nodes and with the SAT
+// solver for larger ones.
+//
+// The search can take exponential time. It stops with the error of ctx when
+// ctx is canceled or its deadline passes.
+func Exact(ctx context.Context, cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+	p, err := newProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make([]int, len(p.neighbors))
+	for v := range colors {
+		colors[v] = uncolored
+	}
+	if err := p.dsaturFill(colors); err == nil {
+		return p.result(cg, colors), nil
+	}
+
+	if len(p.neighbors) <= BacktrackingMaxNodes {
+		colors, err = p.backtrack(ctx)
+	} else {
+		colors, err = p.sat(ctx, cg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+// Backtracking finds a coloring with k colors or returns ErrUnsatisfiable,
+// by trying every color for the nodes in DSatur order. A new color is only
+// tried once per node, since the colors not yet used are interchangeable. It
+// is fast on small graphs, see Exact.
+func Backtracking(ctx context.Context, cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+	p, err := newProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	colors, err := p.backtrack(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+func (p *problem) backtrack(ctx context.Context) ([]int, error) {
+	k := len(p.palette)
+	b := &backtracker{
+		ctx:            ctx,
+		problem:        p,
+		k:              k,
+		colors:         make([]int, len(p.neighbors)),
+		neighborColors: make([]int, len(p.neighbors)*k),
+		saturation:     make([]int, len(p.neighbors)),
+	}
+	for v := range b.colors {
+		b.colors[v] = uncolored
+	}
+
+	found, err := b.search(len(b.colors), 0)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w with %d colors", ErrUnsatisfiable, k)
+	}
+	return b.colors, nil
+}
+
+type backtracker struct {
+	ctx context.Context
+	*problem
+	k      int
+	colors []int
+	// neighborColors[v*k+c] counts the neighbors of v colored c, and
+	// saturation[v] the distinct colors among them
+	neighborColors []int
+	saturation     []int
+	steps          int
+}
+
+// search colors the left uncolored nodes using colors up to used first, and
+// reports whether it succeeded.
+func (b *backtracker) search(left, used int) (bool, error) {
+	if left == 0 {
+		return true, nil
+	}
+
+	b.steps++
+	if b.steps%backtrackCheckInterval == 0 {
+		if err := b.ctx.Err(); err != nil {
+			return false, err
+		}
+	}
+
+	v := uncolored
+	for u, c := range b.colors {
+		if c != uncolored {
+			continue
+		}
+		if v == uncolored || b.saturation[u] > b.saturation[v] ||
+			(b.saturation[u] == b.saturation[v] && len(b.neighbors[u]) > len(b.neighbors[v])) {
+			v = u
+		}
+	}
+	if b.saturation[v] == b.k {
+		return false, nil
+	}
+
+	for c := range min(used+1, b.k) {
+		if b.neighborColors[v*b.k+c] > 0 {
+			continue
+		}
+
+		b.set(v, c)
+		found, err := b.search(left-1, max(used, c+1))
+		if found || err != nil {
+			return found, err
+		}
+		b.unset(v, c)
+	}
+	return false, nil
+}
+
+func (b *backtracker) set(v, c int) {
+	b.colors[v] = c
+	for _, u := range b.neighbors[v] {
+		if b.neighborColors[u*b.k+c] == 0 {
+			b.saturation[u]++
+		}
+		b.neighborColors[u*b.k+c]++
+	}
+}
+
+func (b *backtracker) unset(v, c int) {
+	b.colors[v] = uncolored
+	for _, u := range b.neighbors[v] {
+		b.neighborColors[u*b.k+c]--
+		if b.neighborColors[u*b.k+c] == 0 {
+			b.saturation[u]--
+		}
+	}
+}
+
+// SAT finds a coloring with k colors or returns ErrUnsatisfiable, by solving
+// the formula of EncodeColoring with the built-in CDCL solver. It scales to
+// larger graphs than Backtracking, see Exact.
+func SAT(ctx context.Context, cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+	p, err := newProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	colors, err := p.sat(ctx, cg)
+	if err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+func (p *problem) sat(ctx context.Context, cg *coloringgraph.ColoringGraph) ([]int, error) {
+	k := len(p.palette)
+	model, err := EncodeColoring(cg.Graph, k).Solve(ctx)
+	if errors.Is(err, ErrUnsatisfiable) {
+		return nil, fmt.Errorf("%w with %d colors", ErrUnsatisfiable, k)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make([]int, len(p.neighbors))
+	for v := range colors {
+		for c := range k {
+			if model[ColorVar(v, c, k)] {
+				colors[v] = c
+				break
+			}
+		}
+	}
+	return colors, nil
+}
@@ -0,0 +1,200 @@
+package solve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/graph"
+	"github.com/hvuhsg/zkp/graph/gen"
+)
+
+// mycielski returns the Mycielski graph with chromatic number n: triangle-free
+// for n >= 3, so no large clique shows that n-1 colors are not enough.
+func mycielski(n int) *coloringgraph.ColoringGraph {
+	return &coloringgraph.ColoringGraph{
+		Graph:  gen.Mycielski[coloringgraph.ColorNodeValue](n),
+		Colors: make(map[string]struct{}),
+	}
+}
+
+type exactSolver struct {
+	name  string
+	solve func(context.Context, *coloringgraph.ColoringGraph, int) (*coloringgraph.ColoringGraph, error)
+}
+
+var exactSolvers = []exactSolver{
+	{name: "Exact", solve: Exact},
+	{name: "Backtracking", solve: Backtracking},
+	{name: "SAT", solve: SAT},
+}
+
+func TestExactSolvers(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph *coloringgraph.ColoringGraph
+		k     int
+		sat   bool
+	}{
+		{name: "empty graph", graph: newGraph(0, nil), k: 1, sat: true},
+		{name: "isolated nodes", graph: newGraph(3, nil), k: 1, sat: true},
+		{name: "edge with one color", graph: newGraph(2, [][2]int{{0, 1}}), k: 1},
+		{name: "odd cycle", graph: cycle(7), k: 2},
+		{name: "complete graph", graph: complete(5), k: 5, sat: true},
+		{name: "complete graph too few colors", graph: complete(4), k: 3},
+		{name: "petersen graph", graph: petersen(), k: 3, sat: true},
+		{name: "queen graph", graph: queen(5), k: 5, sat: true},
+		{name: "queen graph too few colors", graph: queen(5), k: 4},
+		{name: "grötzsch graph", graph: mycielski(4), k: 4, sat: true},
+		{name: "grötzsch graph too few colors", graph: mycielski(4), k: 3},
+		{name: "mycielski 5", graph: mycielski(5), k: 4},
+	}
+
+	for _, tt := range tests {
+		for _, s := range exactSolvers {
+			t.Run(tt.name+"/"+s.name, func(t *testing.T) {
+				colored, err := s.solve(context.Background(), tt.graph, tt.k)
+				if !tt.sat {
+					if !errors.Is(err, ErrUnsatisfiable) {
+						t.Fatalf("error = %v, want ErrUnsatisfiable", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("%s failed: %v", s.name, err)
+				}
+				checkColoring(t, tt.graph, colored, tt.k)
+			})
+		}
+	}
+}
+
+func TestExactSolversAgree(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 200 {
+		n := 1 + rng.IntN(12)
+		var edges [][2]int
+		for a := range n {
+			for b := a + 1; b < n; b++ {
+				if rng.IntN(2) == 0 {
+					edges = append(edges, [2]int{a, b})
+				}
+			}
+		}
+		g := newGraph(n, edges)
+
+		for k := 1; k <= 5; k++ {
+			_, backtrackingErr := Backtracking(context.Background(), g, k)
+			_, satErr := SAT(context.Background(), g, k)
+			if (backtrackingErr == nil) != (satErr == nil) {
+				t.Fatalf("graph %d, k = %d: Backtracking error = %v, SAT error = %v", i, k, backtrackingErr, satErr)
+			}
+		}
+	}
+}
+
+func TestExactSolversErrors(t *testing.T) {
+	selfLoop := newGraph(2, [][2]int{{0, 1}, {1, 1}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, s := range exactSolvers {
+		t.Run(s.name, func(t *testing.T) {
+			if _, err := s.solve(context.Background(), cycle(3), 0); !errors.Is(err, ErrInvalidK) {
+				t.Errorf("k = 0: error = %v, want ErrInvalidK", err)
+			}
+			if _, err := s.solve(context.Background(), selfLoop, 3); !errors.Is(err, graph.ErrSelfLoop) {
+				t.Errorf("self-loop: error = %v, want graph.ErrSelfLoop", err)
+			}
+			if _, err := s.solve(ctx, mycielski(6), 5); !errors.Is(err, context.Canceled) {
+				t.Errorf("canceled context: error = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
+
+func TestExactTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := Exact(ctx, mycielski(7), 6); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Exact took %v to stop", elapsed)
+	}
+}
+
+func TestCNFSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		cnf  CNF
+		sat  bool
+	}{
+		{name: "no clauses", cnf: CNF{Vars: 2}, sat: true},
+		{name: "empty clause", cnf: CNF{Vars: 1, Clauses: [][]int{{}}}},
+		{name: "contradiction", cnf: CNF{Vars: 1, Clauses: [][]int{{1}, {-1}}}},
+		{name: "implications", cnf: CNF{Vars: 3, Clauses: [][]int{{1}, {-1, 2}, {-2, 3}}}, sat: true},
+		{name: "tautology", cnf: CNF{Vars: 1, Clauses: [][]int{{1, -1}}}, sat: true},
+		{
+			name: "all assignments excluded",
+			cnf:  CNF{Vars: 2, Clauses: [][]int{{1, 2}, {1, -2}, {-1, 2}, {-1, -2}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := tt.cnf.Solve(context.Background())
+			if !tt.sat {
+				if !errors.Is(err, ErrUnsatisfiable) {
+					t.Fatalf("error = %v, want ErrUnsatisfiable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Solve failed: %v", err)
+			}
+			for _, clause := range tt.cnf.Clauses {
+				satisfied := false
+				for _, lit := range clause {
+					if (lit > 0) == model[max(lit, -lit)] {
+						satisfied = true
+					}
+				}
+				if !satisfied {
+					t.Errorf("clause %v is not satisfied", clause)
+				}
+			}
+		})
+	}
+
+	if _, err := (&CNF{Vars: 1, Clauses: [][]int{{2}}}).Solve(context.Background()); err == nil {
+		t.Errorf("expected an error for a literal out of range")
+	}
+}
+
+func TestCNFWriteDIMACS(t *testing.T) {
+	cnf := EncodeColoring(newGraph(2, [][2]int{{0, 1}}).Graph, 2)
+
+	var buf bytes.Buffer
+	if err := cnf.WriteDIMACS(&buf); err != nil {
+		t.Fatalf("WriteDIMACS failed: %v", err)
+	}
+
+	// At least one color and at most one color per node, one clause per color
+	// for the edge, and the two ends of the edge fixed as a clique
+	expected := "p cnf 4 8\n" +
+		"1 2 0\n-1 -2 0\n" +
+		"3 4 0\n-3 -4 0\n" +
+		"-1 -3 0\n-2 -4 0\n" +
+		"1 0\n4 0\n"
+	if buf.String() != expected {
+		t.Errorf("WriteDIMACS wrote\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
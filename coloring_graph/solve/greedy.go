@@ -0,0 +1,74 @@
+package solve
+
+import (
+	"fmt"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+)
+
+// Greedy colors the nodes by decreasing degree (Welsh-Powell), giving each
+// node the first color none of its neighbors has. It is the fastest solver
+// and needs the most colors.
+func Greedy(cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+	p, err := newProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make([]int, len(p.neighbors))
+	for v := range colors {
+		colors[v] = uncolored
+	}
+	if err := p.greedyFill(colors); err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+// GreedyRepair keeps the conflict-free part of the coloring of cg and colors
+// the other nodes like Greedy.
+func GreedyRepair(cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+	p, colors, err := newRepairProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	p.dropConflicts(colors)
+	if err := p.greedyFill(colors); err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+// greedyFill colors the uncolored nodes by decreasing degree.
+func (p *problem) greedyFill(colors []int) error {
+	taken := make([]bool, len(p.palette))
+	for _, v := range p.degreeOrder() {
+		if colors[v] != uncolored {
+			continue
+		}
+
+		clear(taken)
+		for _, u := range p.neighbors[v] {
+			if colors[u] != uncolored {
+				taken[colors[u]] = true
+			}
+		}
+
+		colors[v] = firstFree(taken)
+		if colors[v] == uncolored {
+			return fmt.Errorf("%w: node %d has neighbors of all %d colors", ErrNoColoring, v, len(p.palette))
+		}
+	}
+	return nil
+}
+
+// firstFree returns the first color that is not taken, or uncolored.
+func firstFree(taken []bool) int {
+	for c, t := range taken {
+		if !t {
+			return c
+		}
+	}
+	return uncolored
+}
@@ -0,0 +1,455 @@
+package solve
+
+import (
+	"context"
+	"fmt"
+)
+
+// satCheckInterval is the number of conflicts between two checks of the
+// context.
+const satCheckInterval = 256
+
+// satRestartBase is the number of conflicts of the shortest run between two
+// restarts; the runs follow the Luby sequence in multiples of it.
+const satRestartBase = 100
+
+// satSolver is a CDCL solver: unit propagation with two watched literals,
+// clause learning at the first unique implication point, activity-based
+// branching and restarts. Learned clauses are kept for the whole search.
+//
+// Inside the solver, variable v is numbered from 0 and its literals are 2v
+// (true) and 2v+1 (false), so that lit^1 is the negation of lit.
+type satSolver struct {
+	clauses [][]int
+	// watches[lit] lists the clauses that watch lit in one of their first two
+	// positions and must be visited when lit becomes false
+	watches [][]int
+
+	// assigns[v] is 1 for true, -1 for false and 0 for unassigned
+	assigns []int8
+	level   []int
+	// reason[v] is the clause that implied v, or -1 for a decision
+	reason []int
+	phase  []bool
+
+	trail    []int
+	trailLim []int
+	qhead    int
+
+	activity []float64
+	bump     float64
+	heap     varHeap
+
+	seen []bool
+}
+
+// Solve looks for an assignment that satisfies the formula and returns it as
+// model[v] for variable v, with model[0] unused. It returns ErrUnsatisfiable
+// when no assignment exists, or the error of ctx when ctx is done first.
+func (cnf *CNF) Solve(ctx context.Context) ([]bool, error) {
+	s := newSATSolver(cnf.Vars)
+	for _, clause := range cnf.Clauses {
+		lits := make([]int, 0, len(clause))
+		for _, lit := range clause {
+			v := lit
+			if v < 0 {
+				v = -v
+			}
+			if v == 0 || v > cnf.Vars {
+				return nil, fmt.Errorf("invalid literal %d with %d variables", lit, cnf.Vars)
+			}
+			internal := 2 * (v - 1)
+			if lit < 0 {
+				internal++
+			}
+			lits = append(lits, internal)
+		}
+		if !s.addClause(lits) {
+			return nil, ErrUnsatisfiable
+		}
+	}
+
+	if err := s.solve(ctx); err != nil {
+		return nil, err
+	}
+
+	model := make([]bool, cnf.Vars+1)
+	for v, value := range s.assigns {
+		model[v+1] = value > 0
+	}
+	return model, nil
+}
+
+func newSATSolver(vars int) *satSolver {
+	s := &satSolver{
+		watches:  make([][]int, 2*vars),
+		assigns:  make([]int8, vars),
+		level:    make([]int, vars),
+		reason:   make([]int, vars),
+		phase:    make([]bool, vars),
+		activity: make([]float64, vars),
+		bump:     1,
+		seen:     make([]bool, vars),
+	}
+	s.heap.activity = s.activity
+	s.heap.index = make([]int, vars)
+	for v := range vars {
+		s.heap.push(v)
+	}
+	return s
+}
+
+// value returns 1 if lit is true, -1 if it is false and 0 if it is unassigned.
+func (s *satSolver) value(lit int) int8 {
+	value := s.assigns[lit>>1]
+	if lit&1 == 1 {
+		return -value
+	}
+	return value
+}
+
+// addClause adds a clause of the formula before the search starts, and
+// reports false if the formula is already unsatisfiable.
+func (s *satSolver) addClause(lits []int) bool {
+	// Drop repeated and false literals and clauses that are already satisfied
+	clause := lits[:0]
+	for _, lit := range lits {
+		switch {
+		case s.value(lit) > 0:
+			return true
+		case s.value(lit) < 0:
+			continue
+		}
+		repeated := false
+		for _, other := range clause {
+			if other == lit^1 {
+				return true
+			}
+			if other == lit {
+				repeated = true
+			}
+		}
+		if !repeated {
+			clause = append(clause, lit)
+		}
+	}
+
+	switch len(clause) {
+	case 0:
+		return false
+	case 1:
+		s.enqueue(clause[0], -1)
+		return s.propagate() < 0
+	}
+
+	s.attach(clause)
+	return true
+}
+
+// attach adds a clause of at least two literals and watches its first two.
+func (s *satSolver) attach(clause []int) int {
+	ci := len(s.clauses)
+	s.clauses = append(s.clauses, clause)
+	s.watches[clause[0]] = append(s.watches[clause[0]], ci)
+	s.watches[clause[1]] = append(s.watches[clause[1]], ci)
+	return ci
+}
+
+func (s *satSolver) enqueue(lit, reason int) {
+	v := lit >> 1
+	s.assigns[v] = 1
+	if lit&1 == 1 {
+		s.assigns[v] = -1
+	}
+	s.level[v] = len(s.trailLim)
+	s.reason[v] = reason
+	s.trail = append(s.trail, lit)
+}
+
+// propagate assigns the literals implied by the trail and returns a clause
+// with all its literals false, or -1 if there is no conflict.
+func (s *satSolver) propagate() int {
+	for s.qhead < len(s.trail) {
+		falseLit := s.trail[s.qhead] ^ 1
+		s.qhead++
+
+		ws := s.watches[falseLit]
+		j := 0
+		for i := 0; i < len(ws); i++ {
+			ci := ws[i]
+			clause := s.clauses[ci]
+
+			// Keep the false literal in the second position
+			if clause[0] == falseLit {
+				clause[0], clause[1] = clause[1], clause[0]
+			}
+			if s.value(clause[0]) > 0 {
+				ws[j] = ci
+				j++
+				continue
+			}
+
+			// Look for another literal to watch
+			moved := false
+			for k := 2; k < len(clause); k++ {
+				if s.value(clause[k]) >= 0 {
+					clause[1], clause[k] = clause[k], clause[1]
+					s.watches[clause[1]] = append(s.watches[clause[1]], ci)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			ws[j] = ci
+			j++
+			if s.value(clause[0]) < 0 {
+				j += copy(ws[j:], ws[i+1:])
+				s.watches[falseLit] = ws[:j]
+				return ci
+			}
+			s.enqueue(clause[0], ci)
+		}
+		s.watches[falseLit] = ws[:j]
+	}
+	return -1
+}
+
+// analyze returns the clause learned from a conflict, with the literal that
+// becomes true after backjumping first, and the level to backjump to.
+func (s *satSolver) analyze(conflict int) ([]int, int) {
+	learnt := []int{-1}
+	current := len(s.trailLim)
+	pending := 0
+	lit := -1
+	index := len(s.trail) - 1
+
+	for {
+		clause := s.clauses[conflict]
+		start := 0
+		if lit >= 0 {
+			// The first literal of a reason is the one it implied
+			start = 1
+		}
+		for _, q := range clause[start:] {
+			v := q >> 1
+			if s.seen[v] || s.level[v] == 0 {
+				continue
+			}
+			s.seen[v] = true
+			s.bumpActivity(v)
+			if s.level[v] == current {
+				pending++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+
+		for !s.seen[s.trail[index]>>1] {
+			index--
+		}
+		lit = s.trail[index]
+		index--
+		conflict = s.reason[lit>>1]
+		s.seen[lit>>1] = false
+		pending--
+		if pending == 0 {
+			break
+		}
+	}
+	learnt[0] = lit ^ 1
+
+	backjump := 0
+	for i := 1; i < len(learnt); i++ {
+		if l := s.level[learnt[i]>>1]; l > backjump {
+			backjump = l
+			learnt[1], learnt[i] = learnt[i], learnt[1]
+		}
+	}
+	for _, q := range learnt[1:] {
+		s.seen[q>>1] = false
+	}
+
+	s.bump /= 0.95
+	return learnt, backjump
+}
+
+func (s *satSolver) bumpActivity(v int) {
+	s.activity[v] += s.bump
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.bump *= 1e-100
+	}
+	if s.heap.contains(v) {
+		s.heap.up(s.heap.index[v])
+	}
+}
+
+// backtrack undoes the assignments above level.
+func (s *satSolver) backtrack(level int) {
+	if len(s.trailLim) <= level {
+		return
+	}
+	for i := len(s.trail) - 1; i >= s.trailLim[level]; i-- {
+		v := s.trail[i] >> 1
+		s.phase[v] = s.assigns[v] > 0
+		s.assigns[v] = 0
+		if !s.heap.contains(v) {
+			s.heap.push(v)
+		}
+	}
+	s.trail = s.trail[:s.trailLim[level]]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = len(s.trail)
+}
+
+// decide returns the unassigned variable with the highest activity, with the
+// value it had last, or -1 when every variable is assigned.
+func (s *satSolver) decide() int {
+	for s.heap.len() > 0 {
+		v := s.heap.pop()
+		if s.assigns[v] != 0 {
+			continue
+		}
+		if s.phase[v] {
+			return 2 * v
+		}
+		return 2*v + 1
+	}
+	return -1
+}
+
+func (s *satSolver) solve(ctx context.Context) error {
+	conflicts := 0
+	restart := 1
+	limit := satRestartBase * luby(restart)
+
+	for {
+		conflict := s.propagate()
+		if conflict >= 0 {
+			if len(s.trailLim) == 0 {
+				return ErrUnsatisfiable
+			}
+			conflicts++
+			if conflicts%satCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+
+			learnt, backjump := s.analyze(conflict)
+			s.backtrack(backjump)
+			if len(learnt) == 1 {
+				s.enqueue(learnt[0], -1)
+			} else {
+				s.enqueue(learnt[0], s.attach(learnt))
+			}
+			continue
+		}
+
+		if conflicts >= limit {
+			conflicts = 0
+			restart++
+			limit = satRestartBase * luby(restart)
+			s.backtrack(0)
+		}
+
+		lit := s.decide()
+		if lit < 0 {
+			return nil
+		}
+		s.trailLim = append(s.trailLim, len(s.trail))
+		s.enqueue(lit, -1)
+	}
+}
+
+// luby returns the i-th element, from 1, of the Luby sequence
+// 1, 1, 2, 1, 1, 2, 4, 1, 1, 2, ...
+func luby(i int) int {
+	for {
+		k := 1
+		for 1<<k-1 < i {
+			k++
+		}
+		if i == 1<<k-1 {
+			return 1 << (k - 1)
+		}
+		// The sequence up to 2^k-1 repeats the one up to 2^(k-1)-1 twice
+		i -= 1<<(k-1) - 1
+	}
+}
+
+// varHeap is a max-heap of variables ordered by activity.
+type varHeap struct {
+	activity []float64
+	vars     []int
+	// index[v] is the position of v in vars, or -1
+	index []int
+}
+
+func (h *varHeap) len() int { return len(h.vars) }
+
+func (h *varHeap) contains(v int) bool {
+	i := h.index[v]
+	return i >= 0 && i < len(h.vars) && h.vars[i] == v
+}
+
+func (h *varHeap) push(v int) {
+	h.index[v] = len(h.vars)
+	h.vars = append(h.vars, v)
+	h.up(len(h.vars) - 1)
+}
+
+func (h *varHeap) pop() int {
+	v := h.vars[0]
+	last := len(h.vars) - 1
+	h.swap(0, last)
+	h.vars = h.vars[:last]
+	h.index[v] = -1
+	if last > 0 {
+		h.down(0)
+	}
+	return v
+}
+
+func (h *varHeap) less(i, j int) bool {
+	return h.activity[h.vars[i]] > h.activity[h.vars[j]]
+}
+
+func (h *varHeap) swap(i, j int) {
+	h.vars[i], h.vars[j] = h.vars[j], h.vars[i]
+	h.index[h.vars[i]] = i
+	h.index[h.vars[j]] = j
+}
+
+func (h *varHeap) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *varHeap) down(i int) {
+	for {
+		child := 2*i + 1
+		if child >= len(h.vars) {
+			return
+		}
+		if right := child + 1; right < len(h.vars) && h.less(right, child) {
+			child = right
+		}
+		if !h.less(child, i) {
+			return
+		}
+		h.swap(i, child)
+		i = child
+	}
+}
@@ -0,0 +1,171 @@
+// Package solve finds colorings of coloring graphs, to be used as witnesses
+// for proofs.
+//
+// Every solver takes the number of colors k and returns a copy of the graph
+// colored with "1" to "k". Greedy, DSatur and Tabucol are heuristics that
+// return ErrNoColoring when they do not find a proper coloring, which does
+// not mean that no coloring with k colors exists.
+//
+// The exact solvers, Exact, Backtracking and SAT, either find a coloring or
+// return ErrUnsatisfiable when none exists. They can take exponential time
+// and take a context to stop them.
+//
+// The Repair variants start from the coloring already on the graph and keep
+// as much of it as they can, changing only nodes that are uncolored, in
+// conflict, or colored with a color that does not fit in the k colors.
+package solve
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/graph"
+)
+
+var (
+	ErrNoColoring = errors.New("no coloring found")
+	ErrInvalidK   = errors.New("number of colors must be positive")
+)
+
+// uncolored marks a node without a color in a coloring.
+const uncolored = -1
+
+// problem is the structure of a graph in the form the solvers use: the
+// neighbors of every node without repetitions, and the names of the k colors.
+type problem struct {
+	neighbors [][]int
+	palette   []string
+}
+
+func newProblem(cg *coloringgraph.ColoringGraph, k int) (*problem, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidK, k)
+	}
+
+	// Duplicate edges do not matter, but no coloring exists with a self-loop
+	// and dangling edges can not be colored
+	if err := cg.Validate(); err != nil {
+		if errors.Is(err, graph.ErrSelfLoop) || errors.Is(err, graph.ErrDanglingEdge) || errors.Is(err, graph.ErrNodeIdMismatch) {
+			return nil, err
+		}
+	}
+
+	p := &problem{
+		neighbors: make([][]int, len(cg.GetNodes())),
+		palette:   make([]string, k),
+	}
+	for v := range p.neighbors {
+		neighbors := slices.Sorted(cg.Neighbors(v))
+		p.neighbors[v] = slices.Compact(neighbors)
+	}
+	for c := range p.palette {
+		p.palette[c] = strconv.Itoa(c + 1)
+	}
+	return p, nil
+}
+
+// newRepairProblem is like newProblem but names the colors after the k most
+// used colors of the graph, and returns the current coloring in terms of them.
+// Nodes whose color is not among them are uncolored.
+func newRepairProblem(cg *coloringgraph.ColoringGraph, k int) (*problem, []int, error) {
+	p, err := newProblem(cg, k)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, node := range cg.GetNodes() {
+		if node.Value != "" {
+			counts[string(node.Value)]++
+		}
+	}
+	used := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	used = used[:min(k, len(used))]
+
+	// Fill the remaining colors with names that are not taken
+	p.palette = append(p.palette[:0], used...)
+	for i := 1; len(p.palette) < k; i++ {
+		if name := strconv.Itoa(i); !slices.Contains(used, name) {
+			p.palette = append(p.palette, name)
+		}
+	}
+
+	index := make(map[string]int, k)
+	for c, name := range p.palette {
+		index[name] = c
+	}
+	colors := make([]int, len(cg.GetNodes()))
+	for v, node := range cg.GetNodes() {
+		c, ok := index[string(node.Value)]
+		if !ok {
+			c = uncolored
+		}
+		colors[v] = c
+	}
+	return p, colors, nil
+}
+
+// dropConflicts uncolors nodes until no two neighbors share a color, each time
+// picking the node in the most conflicts, so few nodes lose their color.
+func (p *problem) dropConflicts(colors []int) {
+	conflicts := make([]int, len(colors))
+	for v, neighbors := range p.neighbors {
+		for _, u := range neighbors {
+			if colors[v] != uncolored && colors[u] == colors[v] {
+				conflicts[v]++
+			}
+		}
+	}
+
+	for {
+		worst := -1
+		for v, n := range conflicts {
+			if n > 0 && (worst < 0 || n > conflicts[worst]) {
+				worst = v
+			}
+		}
+		if worst < 0 {
+			return
+		}
+
+		for _, u := range p.neighbors[worst] {
+			if colors[u] == colors[worst] {
+				conflicts[u]--
+			}
+		}
+		colors[worst] = uncolored
+		conflicts[worst] = 0
+	}
+}
+
+// degreeOrder returns the nodes by decreasing degree, ties by index.
+func (p *problem) degreeOrder() []int {
+	order := make([]int, len(p.neighbors))
+	for v := range order {
+		order[v] = v
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(len(p.neighbors[b]), len(p.neighbors[a]))
+	})
+	return order
+}
+
+// result returns a copy of cg colored with colors.
+func (p *problem) result(cg *coloringgraph.ColoringGraph, colors []int) *coloringgraph.ColoringGraph {
+	colored := cg.Clone()
+	colored.Colors = make(map[string]struct{}, len(p.palette))
+	for _, name := range p.palette {
+		colored.Colors[name] = struct{}{}
+	}
+	for v, c := range colors {
+		colored.ReplaceNodeValue(v, coloringgraph.ColorNodeValue(p.palette[c]))
+	}
+	return colored
+}
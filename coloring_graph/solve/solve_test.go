@@ -0,0 +1,226 @@
+package solve
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/graph"
+)
+
+func newGraph(nodes int, edges [][2]int) *coloringgraph.ColoringGraph {
+	cg := coloringgraph.NewColoringGraph()
+	for range nodes {
+		cg.AddNode("")
+	}
+	for _, edge := range edges {
+		cg.AddEdge(edge[0], edge[1])
+	}
+	return cg
+}
+
+func cycle(n int) *coloringgraph.ColoringGraph {
+	var edges [][2]int
+	for i := range n {
+		edges = append(edges, [2]int{i, (i + 1) % n})
+	}
+	return newGraph(n, edges)
+}
+
+func complete(n int) *coloringgraph.ColoringGraph {
+	var edges [][2]int
+	for i := range n {
+		for j := i + 1; j < n; j++ {
+			edges = append(edges, [2]int{i, j})
+		}
+	}
+	return newGraph(n, edges)
+}
+
+func petersen() *coloringgraph.ColoringGraph {
+	var edges [][2]int
+	for i := range 5 {
+		edges = append(edges, [2]int{i, (i + 1) % 5}, [2]int{i, i + 5}, [2]int{i + 5, (i+2)%5 + 5})
+	}
+	return newGraph(10, edges)
+}
+
+// queen returns the n×n queen graph: squares are adjacent if a queen can move
+// between them. queen(5) needs 5 colors.
+func queen(n int) *coloringgraph.ColoringGraph {
+	var edges [][2]int
+	for a := range n * n {
+		for b := a + 1; b < n*n; b++ {
+			ra, ca, rb, cb := a/n, a%n, b/n, b%n
+			if ra == rb || ca == cb || ra-ca == rb-cb || ra+ca == rb+cb {
+				edges = append(edges, [2]int{a, b})
+			}
+		}
+	}
+	return newGraph(n*n, edges)
+}
+
+type solver struct {
+	name  string
+	solve func(*coloringgraph.ColoringGraph, int) (*coloringgraph.ColoringGraph, error)
+}
+
+var solvers = []solver{
+	{name: "Greedy", solve: Greedy},
+	{name: "DSatur", solve: DSatur},
+	{name: "Tabucol", solve: func(cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+		return Tabucol(cg, k, TabucolOptions{Seed: 1, MaxIterations: 10_000})
+	}},
+}
+
+var repairers = []solver{
+	{name: "GreedyRepair", solve: GreedyRepair},
+	{name: "DSaturRepair", solve: DSaturRepair},
+	{name: "TabucolRepair", solve: func(cg *coloringgraph.ColoringGraph, k int) (*coloringgraph.ColoringGraph, error) {
+		return TabucolRepair(cg, k, TabucolOptions{Seed: 1, MaxIterations: 10_000})
+	}},
+}
+
+func checkColoring(t *testing.T, original, colored *coloringgraph.ColoringGraph, k int) {
+	t.Helper()
+
+	if !colored.IsGraphColoringValid() {
+		t.Fatalf("coloring is not valid")
+	}
+	if len(colored.Colors) != k {
+		t.Errorf("coloring has %d colors, want %d", len(colored.Colors), k)
+	}
+	if colored.Fingerprint() != original.Fingerprint() {
+		t.Errorf("solver changed the graph structure")
+	}
+}
+
+func TestSolvers(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph *coloringgraph.ColoringGraph
+		k     int
+		// greedy is false for graphs Greedy is not expected to color with k colors
+		greedy bool
+	}{
+		{name: "empty graph", graph: newGraph(0, nil), k: 1, greedy: true},
+		{name: "isolated nodes", graph: newGraph(3, nil), k: 1, greedy: true},
+		{name: "even cycle", graph: cycle(6), k: 2, greedy: true},
+		{name: "odd cycle", graph: cycle(7), k: 3, greedy: true},
+		{name: "complete graph", graph: complete(5), k: 5, greedy: true},
+		{name: "petersen graph", graph: petersen(), k: 3, greedy: true},
+		{name: "queen graph", graph: queen(5), k: 5},
+	}
+
+	for _, tt := range tests {
+		for _, s := range solvers {
+			if s.name == "Greedy" && !tt.greedy {
+				continue
+			}
+			t.Run(fmt.Sprintf("%s/%s", tt.name, s.name), func(t *testing.T) {
+				colored, err := s.solve(tt.graph, tt.k)
+				if err != nil {
+					t.Fatalf("%s failed: %v", s.name, err)
+				}
+				checkColoring(t, tt.graph, colored, tt.k)
+
+				for _, node := range tt.graph.GetNodes() {
+					if node.Value != "" {
+						t.Fatalf("solver changed the input graph")
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSolversErrors(t *testing.T) {
+	selfLoop := newGraph(2, [][2]int{{0, 1}, {1, 1}})
+
+	for _, s := range append(solvers, repairers...) {
+		t.Run(s.name, func(t *testing.T) {
+			if _, err := s.solve(complete(4), 3); !errors.Is(err, ErrNoColoring) {
+				t.Errorf("coloring K4 with 3 colors: error = %v, want ErrNoColoring", err)
+			}
+			if _, err := s.solve(cycle(3), 0); !errors.Is(err, ErrInvalidK) {
+				t.Errorf("k = 0: error = %v, want ErrInvalidK", err)
+			}
+			if _, err := s.solve(selfLoop, 3); !errors.Is(err, graph.ErrSelfLoop) {
+				t.Errorf("self-loop: error = %v, want graph.ErrSelfLoop", err)
+			}
+		})
+	}
+}
+
+func TestRepair(t *testing.T) {
+	// A properly colored petersen graph with one node recolored into a conflict
+	base, err := DSatur(petersen(), 3)
+	if err != nil {
+		t.Fatalf("DSatur failed: %v", err)
+	}
+	broken := base.Clone()
+	conflict := base.GetNodes()[1].Value
+	broken.ReplaceNodeValue(0, conflict)
+
+	for _, s := range repairers {
+		t.Run(s.name, func(t *testing.T) {
+			repaired, err := s.solve(broken, 3)
+			if err != nil {
+				t.Fatalf("%s failed: %v", s.name, err)
+			}
+			checkColoring(t, broken, repaired, 3)
+
+			changes := 0
+			for i, node := range repaired.GetNodes() {
+				if node.Value != broken.GetNodes()[i].Value {
+					changes++
+				}
+			}
+			if changes != 1 {
+				t.Errorf("%s changed %d nodes, want 1", s.name, changes)
+			}
+		})
+	}
+}
+
+func TestRepairKeepsColorNames(t *testing.T) {
+	cg := cycle(4)
+	for i, color := range []coloringgraph.ColorNodeValue{"red", "blue", "red", ""} {
+		cg.ReplaceNodeValue(i, color)
+	}
+
+	for _, s := range repairers {
+		t.Run(s.name, func(t *testing.T) {
+			repaired, err := s.solve(cg, 2)
+			if err != nil {
+				t.Fatalf("%s failed: %v", s.name, err)
+			}
+			checkColoring(t, cg, repaired, 2)
+			for i, expected := range []coloringgraph.ColorNodeValue{"red", "blue", "red", "blue"} {
+				if value := repaired.GetNodes()[i].Value; value != expected {
+					t.Errorf("node %d color = %q, want %q", i, value, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestTabucolSeed(t *testing.T) {
+	g := queen(5)
+	opts := TabucolOptions{Seed: 42}
+
+	first, err := Tabucol(g, 5, opts)
+	if err != nil {
+		t.Fatalf("Tabucol failed: %v", err)
+	}
+	second, err := Tabucol(g, 5, opts)
+	if err != nil {
+		t.Fatalf("Tabucol failed: %v", err)
+	}
+	for i, node := range first.GetNodes() {
+		if node.Value != second.GetNodes()[i].Value {
+			t.Fatalf("same seed gave different colorings")
+		}
+	}
+}
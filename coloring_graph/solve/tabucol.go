@@ -0,0 +1,171 @@
+package solve
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+)
+
+// DefaultMaxIterations is the number of moves Tabucol tries when
+// TabucolOptions.MaxIterations is zero.
+const DefaultMaxIterations = 100_000
+
+// TabucolOptions configures the Tabucol search.
+type TabucolOptions struct {
+	// MaxIterations is the number of moves to try before giving up.
+	// Zero means DefaultMaxIterations.
+	MaxIterations int
+
+	// Seed makes the search reproducible: the same graph, k and seed always
+	// give the same coloring.
+	Seed uint64
+}
+
+// Tabucol searches for a coloring with the tabu search of Hertz and de Werra.
+// It starts from a greedy assignment that may have conflicts and keeps moving
+// a conflicting node to the color that removes the most conflicts, without
+// undoing recent moves. It finds colorings with fewer colors than DSatur, at
+// the cost of more time.
+func Tabucol(cg *coloringgraph.ColoringGraph, k int, opts TabucolOptions) (*coloringgraph.ColoringGraph, error) {
+	p, err := newProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make([]int, len(p.neighbors))
+	for v := range colors {
+		colors[v] = uncolored
+	}
+	p.leastConflictsFill(colors)
+
+	if err := p.tabucol(colors, opts); err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+// TabucolRepair runs Tabucol from the coloring of cg instead of from a greedy
+// assignment, so that the search usually only changes the nodes around the
+// conflicts.
+func TabucolRepair(cg *coloringgraph.ColoringGraph, k int, opts TabucolOptions) (*coloringgraph.ColoringGraph, error) {
+	p, colors, err := newRepairProblem(cg, k)
+	if err != nil {
+		return nil, err
+	}
+
+	p.leastConflictsFill(colors)
+	if err := p.tabucol(colors, opts); err != nil {
+		return nil, err
+	}
+	return p.result(cg, colors), nil
+}
+
+// leastConflictsFill gives every uncolored node, by decreasing degree, the
+// first color with the fewest neighbors of that color.
+func (p *problem) leastConflictsFill(colors []int) {
+	counts := make([]int, len(p.palette))
+	for _, v := range p.degreeOrder() {
+		if colors[v] != uncolored {
+			continue
+		}
+
+		clear(counts)
+		for _, u := range p.neighbors[v] {
+			if colors[u] != uncolored {
+				counts[colors[u]]++
+			}
+		}
+
+		best := 0
+		for c, n := range counts {
+			if n < counts[best] {
+				best = c
+			}
+		}
+		colors[v] = best
+	}
+}
+
+// tabucol moves nodes between colors until no two neighbors share a color or
+// the iterations run out. Every node must be colored.
+func (p *problem) tabucol(colors []int, opts TabucolOptions) error {
+	maxIterations := opts.MaxIterations
+	if maxIterations == 0 {
+		maxIterations = DefaultMaxIterations
+	}
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+
+	n, k := len(colors), len(p.palette)
+	if k == 1 {
+		// There is no other color to move a node to
+		maxIterations = 0
+	}
+
+	// gamma[v*k+c] counts the neighbors of v colored c, and tabu[v*k+c] is the
+	// first iteration at which v may move back to c
+	gamma := make([]int, n*k)
+	tabu := make([]int, n*k)
+	conflicts := 0
+	for v, neighbors := range p.neighbors {
+		for _, u := range neighbors {
+			gamma[v*k+colors[u]]++
+			if u > v && colors[u] == colors[v] {
+				conflicts++
+			}
+		}
+	}
+
+	for iteration := 0; conflicts > 0 && iteration < maxIterations; iteration++ {
+		bestVertex, bestColor, bestDelta := -1, -1, 0
+		ties, conflicting := 0, 0
+		for v := range n {
+			current := gamma[v*k+colors[v]]
+			if current == 0 {
+				continue
+			}
+			conflicting++
+
+			for c := range k {
+				if c == colors[v] {
+					continue
+				}
+				delta := gamma[v*k+c] - current
+
+				// A tabu move is only allowed if it reaches a new best
+				if tabu[v*k+c] > iteration && conflicts+delta > 0 {
+					continue
+				}
+
+				switch {
+				case bestVertex < 0 || delta < bestDelta:
+					bestVertex, bestColor, bestDelta, ties = v, c, delta, 1
+				case delta == bestDelta:
+					// Pick uniformly among the best moves
+					ties++
+					if rng.IntN(ties) == 0 {
+						bestVertex, bestColor = v, c
+					}
+				}
+			}
+		}
+		if bestVertex < 0 {
+			// Every move is tabu, wait for the tenures to run out
+			continue
+		}
+
+		old := colors[bestVertex]
+		colors[bestVertex] = bestColor
+		conflicts += bestDelta
+		for _, u := range p.neighbors[bestVertex] {
+			gamma[u*k+old]--
+			gamma[u*k+bestColor]++
+		}
+		tabu[bestVertex*k+old] = iteration + rng.IntN(10) + conflicting*6/10 + 1
+	}
+
+	if conflicts > 0 {
+		return fmt.Errorf("%w: %d conflicts left after %d iterations", ErrNoColoring, conflicts, maxIterations)
+	}
+	return nil
+}
@@ -1,23 +1,43 @@
 package commitmentgraph
 
 import (
-	"crypto/sha1"
+	"crypto/rand"
 	"encoding/hex"
-	"math/rand"
+	"fmt"
+	"io"
 
 	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/graph"
+	"github.com/hvuhsg/zkp/hashing"
 )
 
 const (
 	letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 )
 
-func generateRandomString(n int) string {
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = letterBytes[rand.Intn(len(letterBytes))]
+// SaltLength is the number of random letters appended to every color before
+// it is hashed, about 190 bits of entropy.
+const SaltLength = 32
+
+// generateRandomString reads n uniformly distributed letters from r.
+// Bytes that would bias the result towards the first letters are rejected.
+func generateRandomString(r io.Reader, n int) (string, error) {
+	maxByte := 256 - 256%len(letterBytes)
+
+	b := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(b) < n {
+		chunk := buf[:n-len(b)]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return "", err
+		}
+		for _, c := range chunk {
+			if int(c) < maxByte {
+				b = append(b, letterBytes[int(c)%len(letterBytes)])
+			}
+		}
 	}
-	return string(b)
+	return string(b), nil
 }
 
 type CommitmentGraph struct {
@@ -25,26 +45,86 @@ type CommitmentGraph struct {
 	nodesValues []string
 }
 
+// Options configures how a commitment graph is built.
+type Options struct {
+	// Hash is the algorithm used to commit to the node values.
+	// The zero value means hashing.Default. An algorithm that is not
+	// registered gives an error wrapping hashing.ErrUnknownAlgorithm.
+	Hash hashing.Algorithm
+
+	// Rand is read directly for the color permutation and the salts.
+	// A nil Rand means crypto/rand.Reader. The zkp proofer passes a
+	// math/rand/v2 ChaCha8 stream keyed by a 32-byte seed it reads for the
+	// round from its own entropy source.
+	Rand io.Reader
+}
+
 func NewCommitmentGraph(cg *coloringgraph.ColoringGraph) *CommitmentGraph {
+	commitmentGraph, err := NewCommitmentGraphWithOptions(cg, Options{})
+	if err != nil {
+		// crypto/rand.Reader does not fail
+		panic(err)
+	}
+	return commitmentGraph
+}
+
+func NewCommitmentGraphWithOptions(cg *coloringgraph.ColoringGraph, opts Options) (*CommitmentGraph, error) {
+	hashAlgorithm := opts.Hash
+	if hashAlgorithm == 0 {
+		hashAlgorithm = hashing.Default
+	}
+	if !hashAlgorithm.Available() {
+		return nil, fmt.Errorf("%w: %v", hashing.ErrUnknownAlgorithm, hashAlgorithm)
+	}
+
+	entropy := opts.Rand
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+
+	// Node names are not part of the statement and must not leak into proofs
 	cg = cg.Clone()
-	cg.ShuffleColors()
+	cg.StripNames()
+	if err := cg.ShuffleColorsWithReader(entropy); err != nil {
+		return nil, err
+	}
 
 	nodesValues := make([]string, len(cg.GetNodes()))
 	for i, node := range cg.GetNodes() {
 		nodeStringValue := string(node.Value)
-		randomString := generateRandomString(10)
+		randomString, err := generateRandomString(entropy, SaltLength)
+		if err != nil {
+			return nil, err
+		}
 		nodeValue := nodeStringValue + "|" + randomString
-		hash := sha1.Sum([]byte(nodeValue))
-		node.Value = coloringgraph.ColorNodeValue(hex.EncodeToString(hash[:]))
+		hash := hashAlgorithm.Sum([]byte(nodeValue))
+		node.Value = coloringgraph.ColorNodeValue(hex.EncodeToString(hash))
 		nodesValues[i] = nodeValue
 	}
 
 	return &CommitmentGraph{
 		ColoringGraph: cg,
 		nodesValues:   nodesValues,
-	}
+	}, nil
 }
 
 func (cg *CommitmentGraph) GetNodeValue(id int) string {
 	return cg.nodesValues[id]
 }
+
+// hashLabelDigits is the number of hex digits of a commitment shown in a label.
+const hashLabelDigits = 8
+
+// NodeAttributes is a graph.WriteDOT and graph.WriteGraphML hook that labels
+// every node with the first digits of its commitment and puts the full
+// commitment in the tooltip. It never shows the committed colors.
+func (cg *CommitmentGraph) NodeAttributes() graph.NodeAttributes[coloringgraph.ColorNodeValue] {
+	return func(node *graph.Node[coloringgraph.ColorNodeValue]) map[string]string {
+		commitment := string(node.Value)
+		label := commitment
+		if len(label) > hashLabelDigits {
+			label = label[:hashLabelDigits] + "…"
+		}
+		return map[string]string{"label": label, "tooltip": commitment}
+	}
+}
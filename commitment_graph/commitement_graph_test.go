@@ -1,10 +1,17 @@
 package commitmentgraph
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	mathrand "math/rand/v2"
 	"strings"
 	"testing"
 
 	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/hashing"
 )
 
 func TestRandomStringGeneration(t *testing.T) {
@@ -32,7 +39,10 @@ func TestRandomStringGeneration(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := generateRandomString(tt.length)
+			result, err := generateRandomString(rand.Reader, tt.length)
+			if err != nil {
+				t.Fatalf("generateRandomString(%d) failed: %v", tt.length, err)
+			}
 			if len(result) != tt.expected {
 				t.Errorf("generateRandomString(%d) length = %d, want %d", tt.length, len(result), tt.expected)
 			}
@@ -132,3 +142,123 @@ func TestCommitmentGraphConsistencyWithShuffle(t *testing.T) {
 		t.Error("newCg is not valid")
 	}
 }
+
+func TestCommitmentGraphHashAlgorithm(t *testing.T) {
+	cg := coloringgraph.NewColoringGraph()
+	cg.AddNode("red")
+	cg.AddNode("blue")
+	cg.AddEdge(0, 1)
+
+	for _, alg := range []hashing.Algorithm{hashing.SHA256, hashing.SHA3_256, hashing.BLAKE2b_256} {
+		t.Run(alg.String(), func(t *testing.T) {
+			commitmentGraph, err := NewCommitmentGraphWithOptions(cg, Options{Hash: alg})
+			if err != nil {
+				t.Fatalf("NewCommitmentGraphWithOptions failed: %v", err)
+			}
+			for i, node := range commitmentGraph.GetNodes() {
+				expected := hex.EncodeToString(alg.Sum([]byte(commitmentGraph.GetNodeValue(i))))
+				if string(node.Value) != expected {
+					t.Errorf("node %d commitment = %s, want %s", i, node.Value, expected)
+				}
+			}
+		})
+	}
+
+	if _, err := NewCommitmentGraphWithOptions(cg, Options{Hash: hashing.Algorithm(99)}); !errors.Is(err, hashing.ErrUnknownAlgorithm) {
+		t.Errorf("NewCommitmentGraphWithOptions error = %v, want ErrUnknownAlgorithm", err)
+	}
+}
+
+func TestRandomStringRejectsBiasedBytes(t *testing.T) {
+	// 248 and above would favor the first letters and are skipped
+	data := []byte{0, 248, 255, 61, 62, 250, 1}
+	result, err := generateRandomString(bytes.NewReader(data), 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "a9ab" {
+		t.Errorf("generateRandomString = %s, want a9ab", result)
+	}
+
+	_, err = generateRandomString(bytes.NewReader(data), 10)
+	if !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		t.Errorf("expected an EOF error, got %v", err)
+	}
+}
+
+func TestCommitmentGraphWithEntropy(t *testing.T) {
+	cg := coloringgraph.NewColoringGraph()
+	cg.AddNode("red")
+	cg.AddNode("blue")
+	cg.AddNode("green")
+	cg.AddEdge(0, 1)
+	cg.AddEdge(1, 2)
+
+	var seed [32]byte
+	first, err := NewCommitmentGraphWithOptions(cg, Options{Rand: mathrand.NewChaCha8(seed)})
+	if err != nil {
+		t.Fatalf("NewCommitmentGraphWithOptions failed: %v", err)
+	}
+	second, err := NewCommitmentGraphWithOptions(cg, Options{Rand: mathrand.NewChaCha8(seed)})
+	if err != nil {
+		t.Fatalf("NewCommitmentGraphWithOptions failed: %v", err)
+	}
+	if !bytes.Equal(first.Serialize(), second.Serialize()) {
+		t.Error("same entropy should give the same commitment graph")
+	}
+
+	_, err = NewCommitmentGraphWithOptions(cg, Options{Rand: bytes.NewReader(nil)})
+	if err == nil {
+		t.Error("expected an error from an exhausted entropy source")
+	}
+}
+
+func TestCommitmentGraphNodeAttributes(t *testing.T) {
+	cg := coloringgraph.NewColoringGraph()
+	cg.Colors = map[string]struct{}{"red": {}, "blue": {}}
+	cg.AddNode("red")
+	cg.AddNode("blue")
+	cg.AddEdge(0, 1)
+
+	commitmentGraph := NewCommitmentGraph(cg)
+	attrs := commitmentGraph.NodeAttributes()
+
+	for _, node := range commitmentGraph.GetNodes() {
+		got := attrs(node)
+		commitment := string(node.Value)
+		if got["label"] != commitment[:8]+"…" {
+			t.Errorf("node %d label = %q, want the first 8 digits of %q", node.Id, got["label"], commitment)
+		}
+		if got["tooltip"] != commitment {
+			t.Errorf("node %d tooltip = %q, want %q", node.Id, got["tooltip"], commitment)
+		}
+		if _, ok := got["fillcolor"]; ok {
+			t.Errorf("node %d shows its color", node.Id)
+		}
+	}
+}
+
+func TestCommitmentGraphDropsNames(t *testing.T) {
+	cg := coloringgraph.NewColoringGraph()
+	cg.Colors = map[string]struct{}{"red": {}, "blue": {}}
+	if _, err := cg.AddNamedNode("alice", "red"); err != nil {
+		t.Fatalf("AddNamedNode failed: %v", err)
+	}
+	if _, err := cg.AddNamedNode("bob", "blue"); err != nil {
+		t.Fatalf("AddNamedNode failed: %v", err)
+	}
+	cg.AddEdge(0, 1)
+
+	commitmentGraph := NewCommitmentGraph(cg)
+	for i := range commitmentGraph.GetNodes() {
+		if name, ok := commitmentGraph.NodeName(i); ok {
+			t.Errorf("commitment graph node %d is named %q", i, name)
+		}
+	}
+	if bytes.Contains(commitmentGraph.Serialize(), []byte("alice")) {
+		t.Errorf("serialized commitment graph contains a node name")
+	}
+	if _, ok := cg.NodeID("alice"); !ok {
+		t.Errorf("building the commitment graph removed the names of the original graph")
+	}
+}
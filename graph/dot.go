@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NodeAttributes returns the attributes used to draw a node, such as "label"
+// or "fillcolor". It lets WriteDOT and WriteGraphML render node values they
+// do not know about.
+type NodeAttributes[T NodeValue] func(node *Node[T]) map[string]string
+
+// WriteDOT writes the graph in the Graphviz DOT language as an undirected
+// graph. The nodes are identified by their id, or by their name when every
+// node is named, and get the attributes returned by attrs, which may be nil.
+func WriteDOT[T NodeValue](w io.Writer, g *Graph[T], attrs NodeAttributes[T]) error {
+	bw := bufio.NewWriter(w)
+
+	id := func(n int) string {
+		return strconv.Itoa(n)
+	}
+	if g.allNamed() {
+		id = func(n int) string {
+			if name, ok := g.NodeName(n); ok {
+				return quoteDOT(name)
+			}
+			return strconv.Itoa(n)
+		}
+	}
+
+	fmt.Fprintln(bw, "graph G {")
+	for _, node := range g.nodes {
+		fmt.Fprintf(bw, "  %s", id(node.Id))
+		if attrs != nil {
+			writeDOTAttributes(bw, attrs(node))
+		}
+		fmt.Fprintln(bw, ";")
+	}
+	for _, edge := range g.edges {
+		fmt.Fprintf(bw, "  %s -- %s;\n", id(edge.From), id(edge.To))
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// writeDOTAttributes writes an attribute list sorted by name, so the output
+// does not depend on map iteration order.
+func writeDOTAttributes(w io.Writer, attrs map[string]string) {
+	if len(attrs) == 0 {
+		return
+	}
+
+	io.WriteString(w, " [")
+	for i, name := range slices.Sorted(maps.Keys(attrs)) {
+		if i > 0 {
+			io.WriteString(w, ", ")
+		}
+		fmt.Fprintf(w, "%s=%s", quoteDOT(name), quoteDOT(attrs[name]))
+	}
+	io.WriteString(w, "]")
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteDOT(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1}, [2]int{1, 2})
+
+	tests := []struct {
+		name     string
+		attrs    NodeAttributes[IntNodeValue]
+		expected string
+	}{
+		{
+			name:     "without attributes",
+			expected: "graph G {\n  0;\n  1;\n  2;\n  0 -- 1;\n  1 -- 2;\n}\n",
+		},
+		{
+			name: "with attributes",
+			attrs: func(node *Node[IntNodeValue]) map[string]string {
+				if node.Id == 1 {
+					return nil
+				}
+				return map[string]string{"label": `say "` + strconv.Itoa(int(node.Value)) + `"`, "color": "red"}
+			},
+			expected: "graph G {\n" +
+				"  0 [\"color\"=\"red\", \"label\"=\"say \\\"0\\\"\"];\n" +
+				"  1;\n" +
+				"  2 [\"color\"=\"red\", \"label\"=\"say \\\"2\\\"\"];\n" +
+				"  0 -- 1;\n  1 -- 2;\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteDOT(&buf, g, tt.attrs); err != nil {
+				t.Fatalf("WriteDOT failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("WriteDOT wrote\n%s\nwant\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
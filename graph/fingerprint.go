@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"slices"
+)
+
+// fingerprintDomain separates graph fingerprints from other SHA-256 hashes
+// and versions the canonical encoding.
+const fingerprintDomain = "github.com/hvuhsg/zkp graph fingerprint v1"
+
+// Fingerprint identifies the structure of a graph, see Graph.Fingerprint.
+type Fingerprint [sha256.Size]byte
+
+// Fingerprint returns the SHA-256 hash of the canonical encoding of the graph:
+// the number of nodes followed by the edges written with their smaller index
+// first, sorted and without duplicates. Node values are not included, and the
+// order and direction of the edges do not change the fingerprint.
+func (g *Graph[T]) Fingerprint() Fingerprint {
+	edges := make([][2]int, len(g.edges))
+	for i, edge := range g.edges {
+		edges[i] = canonicalEdge(edge.From, edge.To)
+	}
+	slices.SortFunc(edges, func(a, b [2]int) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+	edges = slices.Compact(edges)
+
+	h := sha256.New()
+	h.Write([]byte(fingerprintDomain))
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(len(g.nodes)))
+	h.Write(buf)
+	binary.BigEndian.PutUint64(buf, uint64(len(edges)))
+	h.Write(buf)
+
+	for _, edge := range edges {
+		binary.BigEndian.PutUint64(buf, uint64(edge[0]))
+		h.Write(buf)
+		binary.BigEndian.PutUint64(buf, uint64(edge[1]))
+		h.Write(buf)
+	}
+
+	var f Fingerprint
+	h.Sum(f[:0])
+	return f
+}
+
+// String returns the fingerprint in hex.
+func (f Fingerprint) String() string {
+	return hex.EncodeToString(f[:])
+}
+
+// Short returns the first 16 hex digits of the fingerprint, for logs and
+// store keys.
+func (f Fingerprint) Short() string {
+	return hex.EncodeToString(f[:8])
+}
+
+// MarshalText encodes the fingerprint in hex.
+func (f Fingerprint) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText decodes a fingerprint written by MarshalText.
+func (f *Fingerprint) UnmarshalText(text []byte) error {
+	if hex.DecodedLen(len(text)) != len(f) {
+		return fmt.Errorf("fingerprint must be %d hex digits, got %d", 2*len(f), len(text))
+	}
+	_, err := hex.Decode(f[:], text)
+	return err
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	reference := newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3})
+
+	tests := []struct {
+		name  string
+		graph *Graph[IntNodeValue]
+		same  bool
+	}{
+		{name: "same graph", graph: newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}), same: true},
+		{name: "edges reordered", graph: newTestGraph(4, [2]int{2, 3}, [2]int{0, 1}, [2]int{1, 2}), same: true},
+		{name: "edges reversed", graph: newTestGraph(4, [2]int{1, 0}, [2]int{2, 1}, [2]int{3, 2}), same: true},
+		{name: "duplicate edge", graph: newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{1, 0}), same: true},
+		{
+			name: "other node values",
+			graph: func() *Graph[IntNodeValue] {
+				g := newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3})
+				g.ReplaceNodeValue(0, 42)
+				return g
+			}(),
+			same: true,
+		},
+		{name: "extra node", graph: newTestGraph(5, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}), same: false},
+		{name: "other edge", graph: newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}, [2]int{1, 3}), same: false},
+		{name: "missing edge", graph: newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}), same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := tt.graph.Fingerprint() == reference.Fingerprint(); same != tt.same {
+				t.Errorf("same fingerprint = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
+
+func TestFingerprintText(t *testing.T) {
+	f := newTestGraph(3, [2]int{0, 1}, [2]int{1, 2}).Fingerprint()
+
+	if len(f.String()) != 64 {
+		t.Errorf("String() = %q, want 64 hex digits", f.String())
+	}
+	if short := f.Short(); len(short) != 16 || !strings.HasPrefix(f.String(), short) {
+		t.Errorf("Short() = %q, want the first 16 digits of %q", short, f.String())
+	}
+
+	text, err := f.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText failed: %v", err)
+	}
+	var decoded Fingerprint
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	if decoded != f {
+		t.Errorf("decoded fingerprint %v, want %v", decoded, f)
+	}
+
+	for _, bad := range []string{"", "abc", strings.Repeat("zz", 32), f.String() + "00"} {
+		if err := decoded.UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("UnmarshalText(%q) succeeded", bad)
+		}
+	}
+}
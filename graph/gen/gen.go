@@ -0,0 +1,276 @@
+// Package gen generates graphs for tests and benchmarks.
+//
+// The random generators take a seed and always return the same graph for the
+// same parameters and seed. The structured generators are deterministic. The
+// nodes of every generated graph have the zero value of T, and the graphs
+// have no self-loops or duplicate edges.
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+var ErrInvalidParameters = errors.New("invalid generator parameters")
+
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+func newGraph[T graph.NodeValue](n int) *graph.Graph[T] {
+	g := graph.NewGraph[T]()
+	var zero T
+	for range n {
+		g.AddNode(zero)
+	}
+	return g
+}
+
+// checkRandomParameters checks the node count and the edge probability of a
+// random generator.
+func checkRandomParameters(n int, p float64) error {
+	if n < 0 {
+		return fmt.Errorf("%w: %d nodes", ErrInvalidParameters, n)
+	}
+	if !(p >= 0 && p <= 1) {
+		return fmt.Errorf("%w: edge probability %v", ErrInvalidParameters, p)
+	}
+	return nil
+}
+
+// ErdosRenyi returns a G(n, p) random graph: each of the n(n-1)/2 possible
+// edges is present with probability p, independently of the others. It
+// returns ErrInvalidParameters when n is negative or p is not in [0, 1].
+func ErdosRenyi[T graph.NodeValue](n int, p float64, seed uint64) (*graph.Graph[T], error) {
+	if err := checkRandomParameters(n, p); err != nil {
+		return nil, err
+	}
+
+	g := newGraph[T](n)
+	randomPairs(n, p, newRand(seed), func(a, b int) {
+		g.AddEdge(a, b)
+	})
+	return g, nil
+}
+
+// Planted returns a random graph with a known coloring with k colors: the
+// nodes are split into k classes of equal size, up to one node, and each edge
+// between two nodes of different classes is present with probability p. The
+// witness gives the color of every node, from 0 to k-1. It returns
+// ErrInvalidParameters when n is negative, k is below 1 or p is not in [0, 1].
+func Planted[T graph.NodeValue](n, k int, p float64, seed uint64) (*graph.Graph[T], []int, error) {
+	if err := checkRandomParameters(n, p); err != nil {
+		return nil, nil, err
+	}
+	if k < 1 {
+		return nil, nil, fmt.Errorf("%w: %d colors", ErrInvalidParameters, k)
+	}
+
+	rng := newRand(seed)
+	witness := make([]int, n)
+	for v := range witness {
+		witness[v] = v % k
+	}
+	rng.Shuffle(n, func(i, j int) {
+		witness[i], witness[j] = witness[j], witness[i]
+	})
+
+	g := newGraph[T](n)
+	randomPairs(n, p, rng, func(a, b int) {
+		if witness[a] != witness[b] {
+			g.AddEdge(a, b)
+		}
+	})
+	return g, witness, nil
+}
+
+// randomPairs calls fn for every pair a < b of n nodes with probability p, in
+// time proportional to the number of calls rather than to n² by skipping a
+// geometrically distributed number of pairs each time (Batagelj and Brandes).
+func randomPairs(n int, p float64, rng *rand.Rand, fn func(a, b int)) {
+	switch {
+	case p <= 0:
+		return
+	case p >= 1:
+		for b := range n {
+			for a := range b {
+				fn(a, b)
+			}
+		}
+		return
+	}
+
+	logq := math.Log(1 - p)
+	b, a := 1, -1
+	for b < n {
+		skip := math.Log(1-rng.Float64()) / logq
+		if skip >= float64(n)*float64(n) {
+			// Past the last pair
+			return
+		}
+		a += 1 + int(skip)
+		for a >= b && b < n {
+			a -= b
+			b++
+		}
+		if b < n {
+			fn(a, b)
+		}
+	}
+}
+
+// Grid returns the rows×cols grid graph, where node r*cols+c is adjacent to
+// the nodes above, below, left and right of it. It is 2-colorable.
+func Grid[T graph.NodeValue](rows, cols int) *graph.Graph[T] {
+	g := newGraph[T](max(rows, 0) * max(cols, 0))
+	for r := range rows {
+		for c := range cols {
+			v := r*cols + c
+			if c+1 < cols {
+				g.AddEdge(v, v+1)
+			}
+			if r+1 < rows {
+				g.AddEdge(v, v+cols)
+			}
+		}
+	}
+	return g
+}
+
+// CompletePartite returns the complete multipartite graph with parts of the
+// given sizes: the nodes of each part come in order, and every two nodes of
+// different parts are adjacent. With k parts it needs exactly k colors.
+func CompletePartite[T graph.NodeValue](sizes ...int) *graph.Graph[T] {
+	var part []int
+	for i, size := range sizes {
+		for range size {
+			part = append(part, i)
+		}
+	}
+
+	g := newGraph[T](len(part))
+	for b := range part {
+		for a := range b {
+			if part[a] != part[b] {
+				g.AddEdge(a, b)
+			}
+		}
+	}
+	return g
+}
+
+// Mycielski returns the Mycielski graph with chromatic number k: a single
+// node for k = 1, an edge for k = 2, and for larger k the Mycielskian of the
+// previous graph. The graphs have no triangles, so they need many more colors
+// than their largest clique, and have 3·2^(k-2) - 1 nodes for k >= 2.
+func Mycielski[T graph.NodeValue](k int) *graph.Graph[T] {
+	if k < 1 {
+		return newGraph[T](0)
+	}
+	if k == 1 {
+		return newGraph[T](1)
+	}
+
+	n, edges := 2, [][2]int{{0, 1}}
+	for range k - 2 {
+		// Every node v gets a shadow n+v adjacent to the neighbors of v, and
+		// all the shadows are adjacent to a new node 2n
+		next := append([][2]int(nil), edges...)
+		for _, edge := range edges {
+			next = append(next, [2]int{edge[0], n + edge[1]}, [2]int{edge[1], n + edge[0]})
+		}
+		for v := range n {
+			next = append(next, [2]int{n + v, 2 * n})
+		}
+		n, edges = 2*n+1, next
+	}
+
+	g := newGraph[T](n)
+	for _, edge := range edges {
+		g.AddEdge(edge[0], edge[1])
+	}
+	return g
+}
+
+// regularAttempts is the number of times RandomRegular restarts when the
+// pairing gets stuck.
+const regularAttempts = 1000
+
+// RandomRegular returns a random graph on n nodes where every node has degree
+// d. It pairs the d copies of every node at random, keeping only the pairs
+// that make neither a self-loop nor a duplicate edge, and starts over when no
+// such pair is left. n·d must be even and d smaller than n.
+func RandomRegular[T graph.NodeValue](n, d int, seed uint64) (*graph.Graph[T], error) {
+	if n < 0 || d < 0 || (n*d)%2 != 0 || (n > 0 && d >= n) {
+		return nil, fmt.Errorf("%w: no %d-regular graph on %d nodes", ErrInvalidParameters, d, n)
+	}
+
+	rng := newRand(seed)
+	for range regularAttempts {
+		if edges, ok := tryRegular(n, d, rng); ok {
+			g := newGraph[T](n)
+			for _, edge := range edges {
+				g.AddEdge(edge[0], edge[1])
+			}
+			return g, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no %d-regular graph on %d nodes found after %d attempts", ErrInvalidParameters, d, n, regularAttempts)
+}
+
+// tryRegular pairs the copies of the nodes until every node has degree d, and
+// reports false when the remaining copies can not be paired.
+func tryRegular(n, d int, rng *rand.Rand) ([][2]int, bool) {
+	edges := make([][2]int, 0, n*d/2)
+	adjacent := make(map[[2]int]bool, n*d/2)
+	pair := func(a, b int) bool {
+		return a != b && !adjacent[[2]int{min(a, b), max(a, b)}]
+	}
+
+	stubs := make([]int, 0, n*d)
+	for v := range n {
+		for range d {
+			stubs = append(stubs, v)
+		}
+	}
+
+	for len(stubs) > 0 {
+		rng.Shuffle(len(stubs), func(i, j int) {
+			stubs[i], stubs[j] = stubs[j], stubs[i]
+		})
+
+		var left []int
+		for i := 0; i+1 < len(stubs); i += 2 {
+			a, b := stubs[i], stubs[i+1]
+			if !pair(a, b) {
+				left = append(left, a, b)
+				continue
+			}
+			edge := [2]int{min(a, b), max(a, b)}
+			adjacent[edge] = true
+			edges = append(edges, edge)
+		}
+
+		if len(left) == len(stubs) && !canPair(left, pair) {
+			return nil, false
+		}
+		stubs = left
+	}
+	return edges, true
+}
+
+// canPair reports whether any two of the stubs can still be paired.
+func canPair(stubs []int, pair func(a, b int) bool) bool {
+	for i, a := range stubs {
+		for _, b := range stubs[i+1:] {
+			if pair(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package gen
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/hvuhsg/zkp/graph"
+)
+
+func degrees(g *graph.Graph[graph.IntNodeValue]) []int {
+	degrees := make([]int, len(g.GetNodes()))
+	for v := range degrees {
+		degrees[v] = g.Degree(v)
+	}
+	return degrees
+}
+
+func TestGenerators(t *testing.T) {
+	grid := Grid[graph.IntNodeValue](3, 4)
+	partite := CompletePartite[graph.IntNodeValue](2, 3, 4)
+	regular, err := RandomRegular[graph.IntNodeValue](50, 3, 1)
+	if err != nil {
+		t.Fatalf("RandomRegular failed: %v", err)
+	}
+	planted, _, err := Planted[graph.IntNodeValue](60, 4, 0.3, 1)
+	if err != nil {
+		t.Fatalf("Planted failed: %v", err)
+	}
+	erdosRenyi := func(n int, p float64) *graph.Graph[graph.IntNodeValue] {
+		g, err := ErdosRenyi[graph.IntNodeValue](n, p, 1)
+		if err != nil {
+			t.Fatalf("ErdosRenyi failed: %v", err)
+		}
+		return g
+	}
+
+	tests := []struct {
+		name  string
+		graph *graph.Graph[graph.IntNodeValue]
+		nodes int
+		// edges is the expected number of edges, or -1 when it is random
+		edges int
+	}{
+		{name: "erdős–rényi", graph: erdosRenyi(100, 0.1), nodes: 100, edges: -1},
+		{name: "erdős–rényi empty", graph: erdosRenyi(10, 0), nodes: 10, edges: 0},
+		{name: "erdős–rényi complete", graph: erdosRenyi(10, 1), nodes: 10, edges: 45},
+		{name: "planted", graph: planted, nodes: 60, edges: -1},
+		{name: "grid", graph: grid, nodes: 12, edges: 3*3 + 2*4},
+		{name: "complete partite", graph: partite, nodes: 9, edges: 2*3 + 2*4 + 3*4},
+		{name: "mycielski 1", graph: Mycielski[graph.IntNodeValue](1), nodes: 1, edges: 0},
+		{name: "mycielski 2", graph: Mycielski[graph.IntNodeValue](2), nodes: 2, edges: 1},
+		{name: "mycielski 3", graph: Mycielski[graph.IntNodeValue](3), nodes: 5, edges: 5},
+		{name: "grötzsch", graph: Mycielski[graph.IntNodeValue](4), nodes: 11, edges: 20},
+		{name: "mycielski 5", graph: Mycielski[graph.IntNodeValue](5), nodes: 23, edges: 71},
+		{name: "random regular", graph: regular, nodes: 50, edges: 75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.graph.Validate(); err != nil {
+				t.Fatalf("generated graph is not valid: %v", err)
+			}
+			if n := len(tt.graph.GetNodes()); n != tt.nodes {
+				t.Errorf("graph has %d nodes, want %d", n, tt.nodes)
+			}
+			if tt.edges >= 0 && len(tt.graph.GetEdges()) != tt.edges {
+				t.Errorf("graph has %d edges, want %d", len(tt.graph.GetEdges()), tt.edges)
+			}
+		})
+	}
+}
+
+func TestErdosRenyiDensity(t *testing.T) {
+	g, err := ErdosRenyi[graph.IntNodeValue](400, 0.05, 7)
+	if err != nil {
+		t.Fatalf("ErdosRenyi failed: %v", err)
+	}
+
+	expected := 0.05 * 400 * 399 / 2
+	if edges := float64(len(g.GetEdges())); edges < 0.9*expected || edges > 1.1*expected {
+		t.Errorf("graph has %v edges, want about %v", edges, expected)
+	}
+
+	invalid := []struct {
+		n int
+		p float64
+	}{{-1, 0.5}, {10, -0.1}, {10, 1.5}, {10, math.NaN()}}
+	for _, tt := range invalid {
+		if _, err := ErdosRenyi[graph.IntNodeValue](tt.n, tt.p, 7); !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("n = %d, p = %v: error = %v, want ErrInvalidParameters", tt.n, tt.p, err)
+		}
+	}
+}
+
+func TestPlantedWitness(t *testing.T) {
+	g, witness, err := Planted[graph.IntNodeValue](101, 3, 0.5, 3)
+	if err != nil {
+		t.Fatalf("Planted failed: %v", err)
+	}
+
+	counts := make([]int, 3)
+	for _, c := range witness {
+		counts[c]++
+	}
+	for c, n := range counts {
+		if n < 33 || n > 34 {
+			t.Errorf("color %d has %d nodes, want 33 or 34", c, n)
+		}
+	}
+	for _, edge := range g.GetEdges() {
+		if witness[edge.From] == witness[edge.To] {
+			t.Fatalf("edge (%d, %d) joins two nodes of color %d", edge.From, edge.To, witness[edge.From])
+		}
+	}
+
+	invalid := []struct {
+		n, k int
+		p    float64
+	}{{10, 0, 0.5}, {-1, 3, 0.5}, {10, 3, -0.1}, {10, 3, 1.5}, {10, 3, math.NaN()}}
+	for _, tt := range invalid {
+		if _, _, err := Planted[graph.IntNodeValue](tt.n, tt.k, tt.p, 3); !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("n = %d, k = %d, p = %v: error = %v, want ErrInvalidParameters", tt.n, tt.k, tt.p, err)
+		}
+	}
+}
+
+func TestRandomRegular(t *testing.T) {
+	for _, d := range []int{0, 1, 2, 5, 9} {
+		g, err := RandomRegular[graph.IntNodeValue](10, d, 5)
+		if err != nil {
+			t.Fatalf("d = %d: RandomRegular failed: %v", d, err)
+		}
+		if err := g.Validate(); err != nil {
+			t.Fatalf("d = %d: generated graph is not valid: %v", d, err)
+		}
+		for v, degree := range degrees(g) {
+			if degree != d {
+				t.Errorf("d = %d: node %d has degree %d", d, v, degree)
+			}
+		}
+	}
+
+	invalid := []struct{ n, d int }{{5, 3}, {4, 4}, {-1, 2}, {4, -1}}
+	for _, tt := range invalid {
+		if _, err := RandomRegular[graph.IntNodeValue](tt.n, tt.d, 5); !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("n = %d, d = %d: error = %v, want ErrInvalidParameters", tt.n, tt.d, err)
+		}
+	}
+}
+
+func TestSeeds(t *testing.T) {
+	generators := map[string]func(seed uint64) *graph.Graph[graph.IntNodeValue]{
+		"erdős–rényi": func(seed uint64) *graph.Graph[graph.IntNodeValue] {
+			g, _ := ErdosRenyi[graph.IntNodeValue](50, 0.2, seed)
+			return g
+		},
+		"planted": func(seed uint64) *graph.Graph[graph.IntNodeValue] {
+			g, _, _ := Planted[graph.IntNodeValue](50, 3, 0.2, seed)
+			return g
+		},
+		"random regular": func(seed uint64) *graph.Graph[graph.IntNodeValue] {
+			g, _ := RandomRegular[graph.IntNodeValue](50, 4, seed)
+			return g
+		},
+	}
+
+	for name, generate := range generators {
+		t.Run(name, func(t *testing.T) {
+			if generate(1).Fingerprint() != generate(1).Fingerprint() {
+				t.Errorf("same seed gave different graphs")
+			}
+			if generate(1).Fingerprint() == generate(2).Fingerprint() {
+				t.Errorf("different seeds gave the same graph")
+			}
+		})
+	}
+}
@@ -1,7 +1,21 @@
 package graph
 
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+)
+
 const (
-	version = 0b00000001
+	versionV1 = 0b00000001
+	versionV2 = 0b00000010
+)
+
+var (
+	ErrNodeNotFound = errors.New("node not found")
+	ErrEdgeNotFound = errors.New("edge not found")
 )
 
 type NodeValue interface {
@@ -11,10 +25,20 @@ type NodeValue interface {
 type Graph[T NodeValue] struct {
 	nodes []*Node[T]
 	edges []Edge
+
+	// adjacency[id] lists the neighbors of node id, once per incident edge.
+	// It is maintained by AddEdge and grows lazily, so it can be shorter
+	// than nodes.
+	adjacency [][]int
+
+	// names[id] is the name of node id, or "" for an unnamed node, and ids is
+	// the reverse mapping. Both are nil until a named node is added.
+	names []string
+	ids   map[string]int
 }
 
 type Node[T NodeValue] struct {
-	Id    uint16
+	Id    int
 	Value T
 }
 
@@ -43,19 +67,130 @@ func (g *Graph[T]) Clone() *Graph[T] {
 	newEdges := make([]Edge, len(g.edges))
 	copy(newEdges, g.edges)
 
+	newAdjacency := make([][]int, len(g.adjacency))
+	for i, neighbors := range g.adjacency {
+		newAdjacency[i] = append([]int(nil), neighbors...)
+	}
+
 	return &Graph[T]{
-		nodes: newNodes,
-		edges: newEdges,
+		nodes:     newNodes,
+		edges:     newEdges,
+		adjacency: newAdjacency,
+		names:     slices.Clone(g.names),
+		ids:       maps.Clone(g.ids),
 	}
 }
 
 func (g *Graph[T]) AddNode(value T) {
-	id := uint16(len(g.nodes))
+	id := len(g.nodes)
 	g.nodes = append(g.nodes, &Node[T]{Id: id, Value: value})
 }
 
+// AddEdge adds an undirected edge between from and to and records it in the
+// adjacency index. Edges with a negative index are stored but not indexed.
 func (g *Graph[T]) AddEdge(from, to int) {
 	g.edges = append(g.edges, Edge{From: from, To: to})
+	g.indexEdge(from, to)
+}
+
+func (g *Graph[T]) indexEdge(from, to int) {
+	if from < 0 || to < 0 {
+		return
+	}
+
+	if needed := max(from, to) + 1; needed > len(g.adjacency) {
+		g.adjacency = append(g.adjacency, make([][]int, needed-len(g.adjacency))...)
+	}
+
+	g.adjacency[from] = append(g.adjacency[from], to)
+	if from != to {
+		g.adjacency[to] = append(g.adjacency[to], from)
+	}
+}
+
+// RemoveEdge removes one edge between from and to, in either direction.
+func (g *Graph[T]) RemoveEdge(from, to int) error {
+	i := slices.IndexFunc(g.edges, func(edge Edge) bool {
+		return (edge.From == from && edge.To == to) || (edge.From == to && edge.To == from)
+	})
+	if i < 0 {
+		return fmt.Errorf("%w: (%d, %d)", ErrEdgeNotFound, from, to)
+	}
+
+	edge := g.edges[i]
+	g.edges = slices.Delete(g.edges, i, i+1)
+	g.unindexEdge(edge.From, edge.To)
+	return nil
+}
+
+func (g *Graph[T]) unindexEdge(from, to int) {
+	if from < 0 || to < 0 {
+		return
+	}
+
+	g.adjacency[from] = removeOne(g.adjacency[from], to)
+	if from != to {
+		g.adjacency[to] = removeOne(g.adjacency[to], from)
+	}
+}
+
+func removeOne(neighbors []int, id int) []int {
+	if i := slices.Index(neighbors, id); i >= 0 {
+		return slices.Delete(neighbors, i, i+1)
+	}
+	return neighbors
+}
+
+// RemoveNode removes the node and every edge incident to it. The nodes after
+// it move down by one so that Node.Id stays equal to the node's index, and the
+// remaining edges are renumbered to match. The returned map gives the new id
+// of every remaining node by its old id.
+func (g *Graph[T]) RemoveNode(id int) (map[int]int, error) {
+	if id < 0 || id >= len(g.nodes) {
+		return nil, fmt.Errorf("%w: %d", ErrNodeNotFound, id)
+	}
+
+	g.nodes = slices.Delete(g.nodes, id, id+1)
+	g.removeName(id)
+	reindex := make(map[int]int, len(g.nodes))
+	for i, node := range g.nodes {
+		reindex[node.Id] = i
+		node.Id = i
+	}
+
+	renumber := func(n int) int {
+		if n > id {
+			return n - 1
+		}
+		return n
+	}
+
+	edges := g.edges[:0]
+	for _, edge := range g.edges {
+		if edge.From == id || edge.To == id {
+			continue
+		}
+		edge.From, edge.To = renumber(edge.From), renumber(edge.To)
+		edges = append(edges, edge)
+	}
+	g.edges = edges
+
+	g.adjacency = make([][]int, len(g.nodes))
+	for _, edge := range g.edges {
+		g.indexEdge(edge.From, edge.To)
+	}
+
+	return reindex, nil
+}
+
+// ReplaceNodeValue sets the value of an existing node.
+func (g *Graph[T]) ReplaceNodeValue(id int, value T) error {
+	if id < 0 || id >= len(g.nodes) {
+		return fmt.Errorf("%w: %d", ErrNodeNotFound, id)
+	}
+
+	g.nodes[id].Value = value
+	return nil
 }
 
 func (g *Graph[T]) GetNodes() []*Node[T] {
@@ -65,3 +200,79 @@ func (g *Graph[T]) GetNodes() []*Node[T] {
 func (g *Graph[T]) GetEdges() []Edge {
 	return g.edges
 }
+
+// Nodes iterates over the nodes in id order.
+func (g *Graph[T]) Nodes() iter.Seq[*Node[T]] {
+	return func(yield func(*Node[T]) bool) {
+		for _, node := range g.nodes {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// Edges iterates over the edges in insertion order.
+func (g *Graph[T]) Edges() iter.Seq[Edge] {
+	return func(yield func(Edge) bool) {
+		for _, edge := range g.edges {
+			if !yield(edge) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbors iterates over the nodes connected to id by an edge. A neighbor
+// connected by several edges is yielded once per edge, and a self-loop yields
+// id itself once.
+func (g *Graph[T]) Neighbors(id int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, neighbor := range g.neighbors(id) {
+			if !yield(neighbor) {
+				return
+			}
+		}
+	}
+}
+
+// Degree returns the number of edges incident to id, counting a self-loop once.
+func (g *Graph[T]) Degree(id int) int {
+	return len(g.neighbors(id))
+}
+
+// HasEdge reports whether there is an edge between a and b in either direction.
+func (g *Graph[T]) HasEdge(a, b int) bool {
+	from, to := g.neighbors(a), b
+	if other := g.neighbors(b); len(other) < len(from) {
+		from, to = other, a
+	}
+
+	for _, neighbor := range from {
+		if neighbor == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Graph[T]) neighbors(id int) []int {
+	if id < 0 || id >= len(g.adjacency) {
+		return nil
+	}
+	return g.adjacency[id]
+}
+
+// newGraphFrom creates a graph from decoded nodes and edges and builds its
+// adjacency index.
+func newGraphFrom[T NodeValue](nodes []*Node[T], edges []Edge) *Graph[T] {
+	g := &Graph[T]{
+		nodes:     nodes,
+		edges:     edges,
+		adjacency: make([][]int, len(nodes)),
+	}
+	for _, edge := range edges {
+		g.indexEdge(edge.From, edge.To)
+	}
+	return g
+}
@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func newTestGraph(nodes int, edges ...[2]int) *Graph[IntNodeValue] {
+	g := NewGraph[IntNodeValue]()
+	for i := range nodes {
+		g.AddNode(IntNodeValue(i))
+	}
+	for _, edge := range edges {
+		g.AddEdge(edge[0], edge[1])
+	}
+	return g
+}
+
+func TestNeighbors(t *testing.T) {
+	g := newTestGraph(5, [2]int{0, 1}, [2]int{0, 2}, [2]int{3, 0}, [2]int{2, 2}, [2]int{1, 0})
+
+	tests := []struct {
+		name     string
+		id       int
+		expected []int
+	}{
+		{name: "several edges", id: 0, expected: []int{1, 2, 3, 1}},
+		{name: "both directions", id: 1, expected: []int{0, 0}},
+		{name: "self-loop", id: 2, expected: []int{0, 2}},
+		{name: "isolated node", id: 4, expected: nil},
+		{name: "negative id", id: -1, expected: nil},
+		{name: "missing node", id: 10, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			neighbors := slices.Collect(g.Neighbors(tt.id))
+			if !slices.Equal(neighbors, tt.expected) {
+				t.Errorf("Neighbors(%d) = %v, want %v", tt.id, neighbors, tt.expected)
+			}
+			if degree := g.Degree(tt.id); degree != len(tt.expected) {
+				t.Errorf("Degree(%d) = %d, want %d", tt.id, degree, len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestHasEdge(t *testing.T) {
+	g := newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}, [2]int{3, 3})
+
+	tests := []struct {
+		name     string
+		a, b     int
+		expected bool
+	}{
+		{name: "edge", a: 0, b: 1, expected: true},
+		{name: "reversed edge", a: 2, b: 1, expected: true},
+		{name: "self-loop", a: 3, b: 3, expected: true},
+		{name: "no edge", a: 0, b: 2, expected: false},
+		{name: "missing node", a: 0, b: 7, expected: false},
+		{name: "negative id", a: -1, b: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.HasEdge(tt.a, tt.b); got != tt.expected {
+				t.Errorf("HasEdge(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddEdgeBeforeNodes(t *testing.T) {
+	g := NewGraph[IntNodeValue]()
+	g.AddEdge(0, 3)
+	g.AddEdge(-1, 0)
+
+	if !g.HasEdge(3, 0) {
+		t.Errorf("edge added before its nodes is not indexed")
+	}
+	if degree := g.Degree(0); degree != 1 {
+		t.Errorf("Degree(0) = %d, want 1", degree)
+	}
+	if edges := len(g.GetEdges()); edges != 2 {
+		t.Errorf("graph has %d edges, want 2", edges)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1}, [2]int{1, 2})
+
+	var ids []int
+	for node := range g.Nodes() {
+		ids = append(ids, node.Id)
+	}
+	if !slices.Equal(ids, []int{0, 1, 2}) {
+		t.Errorf("Nodes() ids = %v, want [0 1 2]", ids)
+	}
+
+	if edges := slices.Collect(g.Edges()); !slices.Equal(edges, g.GetEdges()) {
+		t.Errorf("Edges() = %v, want %v", edges, g.GetEdges())
+	}
+
+	// Breaking out of the loop stops the iteration.
+	count := 0
+	for range g.Neighbors(1) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("iterated %d neighbors after break, want 1", count)
+	}
+}
+
+func TestCloneKeepsAdjacency(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1})
+	clone := g.Clone()
+
+	if !clone.HasEdge(0, 1) {
+		t.Fatalf("clone lost edge (0, 1)")
+	}
+
+	clone.AddEdge(1, 2)
+	if g.HasEdge(1, 2) {
+		t.Errorf("edge added to the clone shows up in the original")
+	}
+	if degree := g.Degree(1); degree != 1 {
+		t.Errorf("original Degree(1) = %d, want 1", degree)
+	}
+}
+
+func TestDeserializeGraphBuildsAdjacency(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1}, [2]int{2, 1})
+
+	v1, err := g.SerializeV1()
+	if err != nil {
+		t.Fatalf("SerializeV1 failed: %v", err)
+	}
+
+	for name, data := range map[string][]byte{"v1": v1, "v2": g.Serialize()} {
+		deserialized, err := DeserializeGraph(data, DeserializeIntNodeValue)
+		if err != nil {
+			t.Fatalf("%s: deserialization failed: %v", name, err)
+		}
+		if neighbors := slices.Collect(deserialized.Neighbors(1)); !slices.Equal(neighbors, []int{0, 2}) {
+			t.Errorf("%s: Neighbors(1) = %v, want [0 2]", name, neighbors)
+		}
+	}
+}
+
+func TestRemoveEdge(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1}, [2]int{1, 2}, [2]int{0, 1})
+
+	if err := g.RemoveEdge(1, 0); err != nil {
+		t.Fatalf("RemoveEdge(1, 0) failed: %v", err)
+	}
+	if edges := g.GetEdges(); !slices.Equal(edges, []Edge{{From: 1, To: 2}, {From: 0, To: 1}}) {
+		t.Errorf("edges = %v, want [(1, 2) (0, 1)]", edges)
+	}
+	if degree := g.Degree(0); degree != 1 {
+		t.Errorf("Degree(0) = %d, want 1", degree)
+	}
+
+	if err := g.RemoveEdge(0, 1); err != nil {
+		t.Fatalf("RemoveEdge(0, 1) failed: %v", err)
+	}
+	if g.HasEdge(0, 1) {
+		t.Errorf("HasEdge(0, 1) after removing both copies")
+	}
+
+	if err := g.RemoveEdge(0, 2); !errors.Is(err, ErrEdgeNotFound) {
+		t.Errorf("RemoveEdge(0, 2) error = %v, want ErrEdgeNotFound", err)
+	}
+}
+
+func TestRemoveNode(t *testing.T) {
+	g := newTestGraph(4, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 0})
+
+	reindex, err := g.RemoveNode(1)
+	if err != nil {
+		t.Fatalf("RemoveNode(1) failed: %v", err)
+	}
+
+	if expected := map[int]int{0: 0, 2: 1, 3: 2}; !maps.Equal(reindex, expected) {
+		t.Errorf("reindex = %v, want %v", reindex, expected)
+	}
+	for i, node := range g.GetNodes() {
+		if node.Id != i {
+			t.Errorf("node at index %d has id %d", i, node.Id)
+		}
+	}
+	if values := []IntNodeValue{g.GetNodes()[0].Value, g.GetNodes()[1].Value, g.GetNodes()[2].Value}; !slices.Equal(values, []IntNodeValue{0, 2, 3}) {
+		t.Errorf("values = %v, want [0 2 3]", values)
+	}
+	if edges := g.GetEdges(); !slices.Equal(edges, []Edge{{From: 1, To: 2}, {From: 2, To: 0}}) {
+		t.Errorf("edges = %v, want [(1, 2) (2, 0)]", edges)
+	}
+	if neighbors := slices.Collect(g.Neighbors(2)); !slices.Equal(neighbors, []int{1, 0}) {
+		t.Errorf("Neighbors(2) = %v, want [1 0]", neighbors)
+	}
+	if degree := g.Degree(3); degree != 0 {
+		t.Errorf("Degree(3) = %d after compaction, want 0", degree)
+	}
+
+	// The graph must still round trip after the mutation.
+	if _, err := DeserializeGraph(g.Serialize(), DeserializeIntNodeValue); err != nil {
+		t.Errorf("deserialization after RemoveNode failed: %v", err)
+	}
+
+	for _, id := range []int{-1, 3} {
+		if _, err := g.RemoveNode(id); !errors.Is(err, ErrNodeNotFound) {
+			t.Errorf("RemoveNode(%d) error = %v, want ErrNodeNotFound", id, err)
+		}
+	}
+}
+
+func TestRemoveNodeKeepsClone(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1}, [2]int{1, 2})
+	clone := g.Clone()
+
+	if _, err := clone.RemoveNode(0); err != nil {
+		t.Fatalf("RemoveNode(0) failed: %v", err)
+	}
+	if len(g.GetNodes()) != 3 || g.GetNodes()[1].Id != 1 || !g.HasEdge(0, 1) {
+		t.Errorf("removing a node from the clone changed the original")
+	}
+}
+
+func TestReplaceNodeValue(t *testing.T) {
+	g := newTestGraph(2, [2]int{0, 1})
+
+	if err := g.ReplaceNodeValue(1, 42); err != nil {
+		t.Fatalf("ReplaceNodeValue(1) failed: %v", err)
+	}
+	if value := g.GetNodes()[1].Value; value != 42 {
+		t.Errorf("value = %d, want 42", value)
+	}
+	if err := g.ReplaceNodeValue(2, 42); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("ReplaceNodeValue(2) error = %v, want ErrNodeNotFound", err)
+	}
+}
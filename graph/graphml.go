@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+var ErrInvalidGraphML = errors.New("invalid GraphML data")
+
+type graphMLDocument struct {
+	XMLName xml.Name       `xml:"graphml"`
+	XMLNS   string         `xml:"xmlns,attr,omitempty"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graphs  []graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string  `xml:"id,attr"`
+	For      string  `xml:"for,attr,omitempty"`
+	Name     string  `xml:"attr.name,attr,omitempty"`
+	Type     string  `xml:"attr.type,attr,omitempty"`
+	YFiles   string  `xml:"yfiles.type,attr,omitempty"`
+	Default  *string `xml:"default,omitempty"`
+	resolved string
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// yedShape is the part of a yEd node graphics element that is read: the fill
+// color and the label of the shape.
+type yedShape struct {
+	Fill struct {
+		Color string `xml:"color,attr"`
+	} `xml:"Fill"`
+	Label string `xml:"NodeLabel"`
+}
+
+// WriteGraphML writes the graph as an undirected GraphML document. The nodes
+// are identified as "n<id>", or by their name when every node is named. The
+// attributes returned by attrs, which may be nil, are written as string data
+// keys named after the attributes.
+func WriteGraphML[T NodeValue](w io.Writer, g *Graph[T], attrs NodeAttributes[T]) error {
+	nodeAttrs := make([]map[string]string, len(g.nodes))
+	names := make(map[string]struct{})
+	if attrs != nil {
+		for i, node := range g.nodes {
+			nodeAttrs[i] = attrs(node)
+			for name := range nodeAttrs[i] {
+				names[name] = struct{}{}
+			}
+		}
+	}
+
+	doc := graphMLDocument{XMLNS: graphMLNamespace}
+	keyIDs := make(map[string]string, len(names))
+	for i, name := range slices.Sorted(maps.Keys(names)) {
+		id := "d" + strconv.Itoa(i)
+		keyIDs[name] = id
+		doc.Keys = append(doc.Keys, graphMLKey{ID: id, For: "node", Name: name, Type: "string"})
+	}
+
+	id := graphMLNodeID
+	if g.allNamed() {
+		id = func(n int) string {
+			if name, ok := g.NodeName(n); ok {
+				return name
+			}
+			return graphMLNodeID(n)
+		}
+	}
+
+	out := graphMLGraph{ID: "G", EdgeDefault: "undirected"}
+	for i, node := range g.nodes {
+		n := graphMLNode{ID: id(node.Id)}
+		for _, name := range slices.Sorted(maps.Keys(nodeAttrs[i])) {
+			n.Data = append(n.Data, graphMLData{Key: keyIDs[name], Value: nodeAttrs[i][name]})
+		}
+		out.Nodes = append(out.Nodes, n)
+	}
+	for _, edge := range g.edges {
+		out.Edges = append(out.Edges, graphMLEdge{Source: id(edge.From), Target: id(edge.To)})
+	}
+	doc.Graphs = []graphMLGraph{out}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func graphMLNodeID(id int) string {
+	return "n" + strconv.Itoa(id)
+}
+
+// MarshalXML writes the data value as text. The inner XML is only used when
+// reading.
+func (d graphMLData) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = []xml.Attr{{Name: xml.Name{Local: "key"}, Value: d.Key}}
+	return e.EncodeElement(d.Value, start)
+}
+
+// ReadGraphML reads the first graph of a GraphML document, such as the ones
+// Gephi and yEd write. Nodes get indices in document order and are named by
+// their GraphML id, and edges are read as undirected. The value of each node
+// is made by value from the node's data, keyed by attribute name and including
+// key defaults.
+//
+// Two tool-specific encodings are translated: yEd node graphics become the
+// "fillcolor" and "label" attributes, and Gephi's "r", "g" and "b" attributes
+// become a "fillcolor" of the form "#rrggbb".
+func ReadGraphML[T NodeValue](r io.Reader, value func(attrs map[string]string) (T, error)) (*Graph[T], error) {
+	var doc graphMLDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGraphML, err)
+	}
+	if len(doc.Graphs) == 0 {
+		return nil, fmt.Errorf("%w: no graph", ErrInvalidGraphML)
+	}
+
+	keys := make(map[string]*graphMLKey, len(doc.Keys))
+	defaults := make(map[string]string)
+	for i := range doc.Keys {
+		key := &doc.Keys[i]
+		if key.For != "" && key.For != "node" && key.For != "all" {
+			continue
+		}
+		key.resolved = key.Name
+		if key.resolved == "" {
+			key.resolved = key.ID
+		}
+		keys[key.ID] = key
+		if key.Default != nil {
+			defaults[key.resolved] = strings.TrimSpace(*key.Default)
+		}
+	}
+
+	in := doc.Graphs[0]
+	g := NewGraph[T]()
+	for _, n := range in.Nodes {
+		if _, ok := g.NodeID(n.ID); ok {
+			return nil, fmt.Errorf("%w: duplicate node id %q", ErrInvalidGraphML, n.ID)
+		}
+
+		attrs := maps.Clone(defaults)
+		for _, data := range n.Data {
+			key, ok := keys[data.Key]
+			if !ok {
+				continue
+			}
+			if key.YFiles == "nodegraphics" {
+				readYEdShape(data.Inner, attrs)
+				continue
+			}
+			attrs[key.resolved] = strings.TrimSpace(data.Value)
+		}
+		readGephiColor(attrs)
+
+		v, err := value(attrs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %q: %w", ErrInvalidGraphML, n.ID, err)
+		}
+		if _, err := g.AddNamedNode(n.ID, v); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGraphML, err)
+		}
+	}
+
+	for _, e := range in.Edges {
+		from, ok := g.NodeID(e.Source)
+		if !ok {
+			return nil, fmt.Errorf("%w: edge from unknown node %q", ErrInvalidGraphML, e.Source)
+		}
+		to, ok := g.NodeID(e.Target)
+		if !ok {
+			return nil, fmt.Errorf("%w: edge to unknown node %q", ErrInvalidGraphML, e.Target)
+		}
+		g.AddEdge(from, to)
+	}
+
+	return g, nil
+}
+
+func readYEdShape(inner string, attrs map[string]string) {
+	var shape yedShape
+	if err := xml.Unmarshal([]byte(inner), &shape); err != nil {
+		return
+	}
+	if shape.Fill.Color != "" {
+		attrs["fillcolor"] = shape.Fill.Color
+	}
+	if label := strings.TrimSpace(shape.Label); label != "" {
+		attrs["label"] = label
+	}
+}
+
+func readGephiColor(attrs map[string]string) {
+	if _, ok := attrs["fillcolor"]; ok {
+		return
+	}
+
+	var rgb [3]uint64
+	for i, name := range []string{"r", "g", "b"} {
+		c, err := strconv.ParseUint(attrs[name], 10, 8)
+		if err != nil {
+			return
+		}
+		rgb[i] = c
+	}
+	attrs["fillcolor"] = fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func intValueFromAttrs(attrs map[string]string) (IntNodeValue, error) {
+	v, err := strconv.Atoi(attrs["value"])
+	return IntNodeValue(v), err
+}
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 0})
+	g.ReplaceNodeValue(2, 42)
+
+	var buf bytes.Buffer
+	err := WriteGraphML(&buf, g, func(node *Node[IntNodeValue]) map[string]string {
+		return map[string]string{"value": strconv.Itoa(int(node.Value)), "label": "<n" + strconv.Itoa(node.Id) + ">"}
+	})
+	if err != nil {
+		t.Fatalf("WriteGraphML failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `xmlns="http://graphml.graphdrawing.org/xmlns"`) {
+		t.Errorf("document has no GraphML namespace:\n%s", buf.String())
+	}
+
+	read, err := ReadGraphML(&buf, intValueFromAttrs)
+	if err != nil {
+		t.Fatalf("ReadGraphML failed: %v", err)
+	}
+	if read.Fingerprint() != g.Fingerprint() {
+		t.Errorf("round trip changed the graph structure")
+	}
+	for i, node := range read.GetNodes() {
+		if node.Value != g.GetNodes()[i].Value {
+			t.Errorf("node %d value = %d, want %d", i, node.Value, g.GetNodes()[i].Value)
+		}
+	}
+}
+
+const yedGraphML = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:y="http://www.yworks.com/xml/graphml">
+  <key for="node" id="d6" yfiles.type="nodegraphics"/>
+  <key attr.name="description" attr.type="string" for="node" id="d5"/>
+  <graph edgedefault="directed" id="G">
+    <node id="a">
+      <data key="d6">
+        <y:ShapeNode>
+          <y:Fill color="#FF0000" transparent="false"/>
+          <y:NodeLabel>first</y:NodeLabel>
+        </y:ShapeNode>
+      </data>
+    </node>
+    <node id="b">
+      <data key="d6">
+        <y:ShapeNode>
+          <y:Fill color="#0000FF" transparent="false"/>
+          <y:NodeLabel>second</y:NodeLabel>
+        </y:ShapeNode>
+      </data>
+    </node>
+    <edge id="e0" source="b" target="a"/>
+  </graph>
+</graphml>`
+
+const gephiGraphML = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key attr.name="label" attr.type="string" for="node" id="label"/>
+  <key attr.name="r" attr.type="int" for="node" id="r"/>
+  <key attr.name="g" attr.type="int" for="node" id="g"/>
+  <key attr.name="b" attr.type="int" for="node" id="b"/>
+  <key attr.name="weight" attr.type="double" for="edge" id="weight"/>
+  <key attr.name="group" attr.type="string" for="node" id="group"><default>none</default></key>
+  <graph defaultedgetype="undirected">
+    <node id="0"><data key="label">x</data><data key="r">255</data><data key="g">0</data><data key="b">16</data></node>
+    <node id="1"><data key="label">y</data><data key="group">left</data></node>
+    <edge source="0" target="1"><data key="weight">1.0</data></edge>
+  </graph>
+</graphml>`
+
+func TestReadGraphMLTools(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []map[string]string
+	}{
+		{
+			name: "yEd",
+			data: yedGraphML,
+			expected: []map[string]string{
+				{"fillcolor": "#FF0000", "label": "first"},
+				{"fillcolor": "#0000FF", "label": "second"},
+			},
+		},
+		{
+			name: "Gephi",
+			data: gephiGraphML,
+			expected: []map[string]string{
+				{"label": "x", "r": "255", "g": "0", "b": "16", "group": "none", "fillcolor": "#ff0010"},
+				{"label": "y", "group": "left"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attrs []map[string]string
+			g, err := ReadGraphML(strings.NewReader(tt.data), func(a map[string]string) (IntNodeValue, error) {
+				attrs = append(attrs, a)
+				return IntNodeValue(len(attrs)), nil
+			})
+			if err != nil {
+				t.Fatalf("ReadGraphML failed: %v", err)
+			}
+
+			if len(attrs) != len(tt.expected) {
+				t.Fatalf("read %d nodes, want %d", len(attrs), len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				if len(attrs[i]) != len(expected) {
+					t.Errorf("node %d attributes = %v, want %v", i, attrs[i], expected)
+					continue
+				}
+				for name, value := range expected {
+					if attrs[i][name] != value {
+						t.Errorf("node %d attribute %q = %q, want %q", i, name, attrs[i][name], value)
+					}
+				}
+			}
+			if !g.HasEdge(0, 1) || len(g.GetEdges()) != 1 {
+				t.Errorf("edges = %v, want one edge between 0 and 1", g.GetEdges())
+			}
+		})
+	}
+}
+
+func TestReadGraphMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not XML", data: "graph G {}"},
+		{name: "no graph", data: `<graphml xmlns="http://graphml.graphdrawing.org/xmlns"></graphml>`},
+		{name: "duplicate node", data: `<graphml><graph><node id="a"/><node id="a"/></graph></graphml>`},
+		{name: "unknown source", data: `<graphml><graph><node id="a"/><edge source="b" target="a"/></graph></graphml>`},
+		{name: "unknown target", data: `<graphml><graph><node id="a"/><edge source="a" target="b"/></graph></graphml>`},
+		{name: "invalid value", data: `<graphml><key id="v" attr.name="value" for="node"/><graph><node id="a"><data key="v">x</data></node></graph></graphml>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadGraphML(strings.NewReader(tt.data), intValueFromAttrs); !errors.Is(err, ErrInvalidGraphML) {
+				t.Errorf("ReadGraphML error = %v, want ErrInvalidGraphML", err)
+			}
+		})
+	}
+}
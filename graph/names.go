@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrDuplicateName = errors.New("node name already used")
+	ErrNameNotFound  = errors.New("node name not found")
+	ErrEmptyName     = errors.New("node name is empty")
+)
+
+// AddNamedNode adds a node that can also be found by name, and returns its id.
+// Names are unique within a graph and must not be empty.
+//
+// Names only live in memory: they are not serialized and commitment graphs
+// drop them, so they never appear in proofs.
+func (g *Graph[T]) AddNamedNode(name string, value T) (int, error) {
+	if name == "" {
+		return 0, ErrEmptyName
+	}
+	if _, ok := g.ids[name]; ok {
+		return 0, fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+
+	id := len(g.nodes)
+	g.AddNode(value)
+
+	if g.ids == nil {
+		g.ids = make(map[string]int)
+	}
+	g.names = append(g.names, make([]string, id+1-len(g.names))...)
+	g.names[id] = name
+	g.ids[name] = id
+	return id, nil
+}
+
+// AddEdgeByName adds an edge between the nodes with the given names.
+func (g *Graph[T]) AddEdgeByName(a, b string) error {
+	from, ok := g.NodeID(a)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrNameNotFound, a)
+	}
+	to, ok := g.NodeID(b)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrNameNotFound, b)
+	}
+
+	g.AddEdge(from, to)
+	return nil
+}
+
+// NodeID returns the id of the node with the given name.
+func (g *Graph[T]) NodeID(name string) (int, bool) {
+	id, ok := g.ids[name]
+	return id, ok
+}
+
+// NodeName returns the name of the node with the given id, if it has one.
+func (g *Graph[T]) NodeName(id int) (string, bool) {
+	if id < 0 || id >= len(g.names) || g.names[id] == "" {
+		return "", false
+	}
+	return g.names[id], true
+}
+
+// StripNames removes the names of all nodes. The nodes and their ids stay.
+func (g *Graph[T]) StripNames() {
+	g.names = nil
+	g.ids = nil
+}
+
+// allNamed reports whether every node has a name, in which case exporters
+// identify the nodes by name instead of by id.
+func (g *Graph[T]) allNamed() bool {
+	return len(g.nodes) > 0 && len(g.ids) == len(g.nodes)
+}
+
+// removeName drops the name of a removed node and shifts the names after it.
+func (g *Graph[T]) removeName(id int) {
+	if id >= len(g.names) {
+		return
+	}
+
+	if name := g.names[id]; name != "" {
+		delete(g.ids, name)
+	}
+	g.names = slices.Delete(g.names, id, id+1)
+	for i := id; i < len(g.names); i++ {
+		if g.names[i] != "" {
+			g.ids[g.names[i]] = i
+		}
+	}
+}
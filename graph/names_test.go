@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newNamedTestGraph(t *testing.T) *Graph[IntNodeValue] {
+	t.Helper()
+
+	g := NewGraph[IntNodeValue]()
+	for i, name := range []string{"alice", "bob", "carol"} {
+		id, err := g.AddNamedNode(name, IntNodeValue(i))
+		if err != nil {
+			t.Fatalf("AddNamedNode(%q) failed: %v", name, err)
+		}
+		if id != i {
+			t.Fatalf("AddNamedNode(%q) = %d, want %d", name, id, i)
+		}
+	}
+	if err := g.AddEdgeByName("alice", "bob"); err != nil {
+		t.Fatalf("AddEdgeByName failed: %v", err)
+	}
+	if err := g.AddEdgeByName("carol", "bob"); err != nil {
+		t.Fatalf("AddEdgeByName failed: %v", err)
+	}
+	return g
+}
+
+func TestNamedNodes(t *testing.T) {
+	g := newNamedTestGraph(t)
+	g.AddNode(42)
+
+	if id, ok := g.NodeID("carol"); !ok || id != 2 {
+		t.Errorf("NodeID(carol) = %d, %v, want 2, true", id, ok)
+	}
+	if _, ok := g.NodeID("dave"); ok {
+		t.Errorf("NodeID(dave) found a node")
+	}
+	if name, ok := g.NodeName(1); !ok || name != "bob" {
+		t.Errorf("NodeName(1) = %q, %v, want bob, true", name, ok)
+	}
+	for _, id := range []int{-1, 3, 4} {
+		if name, ok := g.NodeName(id); ok {
+			t.Errorf("NodeName(%d) = %q, want no name", id, name)
+		}
+	}
+	if !g.HasEdge(0, 1) || !g.HasEdge(1, 2) {
+		t.Errorf("edges = %v, want (0, 1) and (2, 1)", g.GetEdges())
+	}
+}
+
+func TestNamedNodesErrors(t *testing.T) {
+	g := newNamedTestGraph(t)
+
+	if _, err := g.AddNamedNode("alice", 7); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("AddNamedNode(alice) error = %v, want ErrDuplicateName", err)
+	}
+	if _, err := g.AddNamedNode("", 7); !errors.Is(err, ErrEmptyName) {
+		t.Errorf("AddNamedNode(\"\") error = %v, want ErrEmptyName", err)
+	}
+	if err := g.AddEdgeByName("alice", "dave"); !errors.Is(err, ErrNameNotFound) {
+		t.Errorf("AddEdgeByName(alice, dave) error = %v, want ErrNameNotFound", err)
+	}
+	if len(g.GetNodes()) != 3 || len(g.GetEdges()) != 2 {
+		t.Errorf("failed calls changed the graph")
+	}
+}
+
+func TestNamesFollowMutations(t *testing.T) {
+	g := newNamedTestGraph(t)
+
+	clone := g.Clone()
+	if _, err := clone.AddNamedNode("dave", 3); err != nil {
+		t.Fatalf("AddNamedNode(dave) on the clone failed: %v", err)
+	}
+	if _, ok := g.NodeID("dave"); ok {
+		t.Errorf("name added to the clone shows up in the original")
+	}
+
+	if _, err := g.RemoveNode(0); err != nil {
+		t.Fatalf("RemoveNode(0) failed: %v", err)
+	}
+	if _, ok := g.NodeID("alice"); ok {
+		t.Errorf("removed node is still found by name")
+	}
+	for name, expected := range map[string]int{"bob": 0, "carol": 1} {
+		if id, ok := g.NodeID(name); !ok || id != expected {
+			t.Errorf("NodeID(%s) = %d, %v, want %d", name, id, ok, expected)
+		}
+		if got, _ := g.NodeName(expected); got != name {
+			t.Errorf("NodeName(%d) = %q, want %q", expected, got, name)
+		}
+	}
+
+	g.StripNames()
+	if _, ok := g.NodeID("bob"); ok {
+		t.Errorf("NodeID(bob) found a node after StripNames")
+	}
+	if len(g.GetNodes()) != 2 {
+		t.Errorf("StripNames removed nodes")
+	}
+}
+
+func TestExportersUseNames(t *testing.T) {
+	g := newNamedTestGraph(t)
+
+	var dot bytes.Buffer
+	if err := WriteDOT(&dot, g, nil); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	if !strings.Contains(dot.String(), `"alice" -- "bob";`) {
+		t.Errorf("DOT output does not use the names:\n%s", dot.String())
+	}
+
+	var graphML bytes.Buffer
+	if err := WriteGraphML(&graphML, g, nil); err != nil {
+		t.Fatalf("WriteGraphML failed: %v", err)
+	}
+	fromGraphML, err := ReadGraphML(&graphML, func(map[string]string) (IntNodeValue, error) { return 0, nil })
+	if err != nil {
+		t.Fatalf("ReadGraphML failed: %v", err)
+	}
+
+	var nodeLink bytes.Buffer
+	if err := WriteNodeLink(&nodeLink, g, nil); err != nil {
+		t.Fatalf("WriteNodeLink failed: %v", err)
+	}
+	fromNodeLink, err := ReadNodeLink(&nodeLink, func(map[string]string) (IntNodeValue, error) { return 0, nil })
+	if err != nil {
+		t.Fatalf("ReadNodeLink failed: %v", err)
+	}
+
+	for format, read := range map[string]*Graph[IntNodeValue]{"GraphML": fromGraphML, "node-link": fromNodeLink} {
+		for i, name := range []string{"alice", "bob", "carol"} {
+			if got, _ := read.NodeName(i); got != name {
+				t.Errorf("%s: NodeName(%d) = %q, want %q", format, i, got, name)
+			}
+		}
+		if read.Fingerprint() != g.Fingerprint() {
+			t.Errorf("%s: round trip changed the graph structure", format)
+		}
+	}
+
+	// A partly named graph is written with ids
+	g.AddNode(3)
+	dot.Reset()
+	if err := WriteDOT(&dot, g, nil); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	if !strings.Contains(dot.String(), "  0 -- 1;") {
+		t.Errorf("DOT output of a partly named graph does not use ids:\n%s", dot.String())
+	}
+}
@@ -0,0 +1,186 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrInvalidNodeLink = errors.New("invalid node-link data")
+
+type nodeLinkDocument struct {
+	Directed   bool                         `json:"directed"`
+	Multigraph bool                         `json:"multigraph"`
+	Graph      map[string]any               `json:"graph"`
+	Nodes      []map[string]json.RawMessage `json:"nodes"`
+	Links      []nodeLinkEdge               `json:"links"`
+	Edges      []nodeLinkEdge               `json:"edges,omitempty"`
+}
+
+type nodeLinkEdge struct {
+	Source json.RawMessage `json:"source"`
+	Target json.RawMessage `json:"target"`
+}
+
+// WriteNodeLink writes the graph in the node-link JSON format of networkx, as
+// read by networkx.node_link_graph. The nodes are identified by their id, or
+// by their name when every node is named, and get the attributes returned by
+// attrs, which may be nil. The edges are written under "links", the key older
+// networkx versions expect; pass edges="links" to versions that default to
+// "edges".
+func WriteNodeLink[T NodeValue](w io.Writer, g *Graph[T], attrs NodeAttributes[T]) error {
+	doc := nodeLinkDocument{
+		Graph: map[string]any{},
+		Nodes: make([]map[string]json.RawMessage, len(g.nodes)),
+		Links: make([]nodeLinkEdge, len(g.edges)),
+	}
+
+	id := nodeLinkID
+	if g.allNamed() {
+		id = func(n int) json.RawMessage {
+			if name, ok := g.NodeName(n); ok {
+				encoded, _ := json.Marshal(name)
+				return encoded
+			}
+			return nodeLinkID(n)
+		}
+	}
+
+	for i, node := range g.nodes {
+		n := map[string]json.RawMessage{"id": id(node.Id)}
+		if attrs != nil {
+			for name, value := range attrs(node) {
+				if name == "id" {
+					continue
+				}
+				encoded, err := json.Marshal(value)
+				if err != nil {
+					return err
+				}
+				n[name] = encoded
+			}
+		}
+		doc.Nodes[i] = n
+	}
+	for i, edge := range g.edges {
+		doc.Links[i] = nodeLinkEdge{Source: id(edge.From), Target: id(edge.To)}
+	}
+
+	return json.NewEncoder(w).Encode(doc)
+}
+
+func nodeLinkID(id int) json.RawMessage {
+	return json.RawMessage(fmt.Sprint(id))
+}
+
+// ReadNodeLink reads a graph written by networkx.node_link_data, with its edges
+// under "links" or "edges". Node keys can be any JSON value and get indices in
+// document order. Nodes are named by their key: a string key as it is and
+// other keys in their JSON form, so the keys 1 and "1" can not be used in the
+// same graph. Edges are read as undirected. The value of each node is made by
+// value from the node's attributes: strings as they are and other JSON values
+// in their JSON form, so the number 1 becomes "1".
+func ReadNodeLink[T NodeValue](r io.Reader, value func(attrs map[string]string) (T, error)) (*Graph[T], error) {
+	var doc nodeLinkDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidNodeLink, err)
+	}
+	if doc.Nodes == nil {
+		return nil, fmt.Errorf("%w: no nodes", ErrInvalidNodeLink)
+	}
+	if doc.Links != nil && doc.Edges != nil {
+		return nil, fmt.Errorf("%w: both links and edges", ErrInvalidNodeLink)
+	}
+
+	g := NewGraph[T]()
+	keys := make(map[string]int, len(doc.Nodes))
+	for i, n := range doc.Nodes {
+		rawID, ok := n["id"]
+		if !ok {
+			return nil, fmt.Errorf("%w: node %d has no id", ErrInvalidNodeLink, i)
+		}
+		id, err := nodeLinkKey(rawID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %d: %w", ErrInvalidNodeLink, i, err)
+		}
+		if _, ok := keys[id]; ok {
+			return nil, fmt.Errorf("%w: duplicate node id %s", ErrInvalidNodeLink, id)
+		}
+
+		attrs := make(map[string]string, len(n)-1)
+		for name, raw := range n {
+			if name == "id" {
+				continue
+			}
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				attrs[name] = s
+				continue
+			}
+			attrs[name] = string(raw)
+		}
+
+		v, err := value(attrs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %s: %w", ErrInvalidNodeLink, id, err)
+		}
+		name := id
+		if err := json.Unmarshal(rawID, &name); err != nil {
+			name = id
+		}
+		index, err := g.AddNamedNode(name, v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %s: %w", ErrInvalidNodeLink, id, err)
+		}
+		keys[id] = index
+	}
+
+	edges := doc.Links
+	if edges == nil {
+		edges = doc.Edges
+	}
+	for i, e := range edges {
+		from, err := nodeLinkEndpoint(keys, e.Source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: edge %d source: %w", ErrInvalidNodeLink, i, err)
+		}
+		to, err := nodeLinkEndpoint(keys, e.Target)
+		if err != nil {
+			return nil, fmt.Errorf("%w: edge %d target: %w", ErrInvalidNodeLink, i, err)
+		}
+		g.AddEdge(from, to)
+	}
+
+	return g, nil
+}
+
+// nodeLinkKey returns the compact JSON form of a node key, so that equal keys
+// written with different spacing match while the string "1" and the number 1
+// stay different nodes.
+func nodeLinkKey(raw json.RawMessage) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return "", err
+	}
+	if buf.String() == "null" {
+		return "", errors.New("null node id")
+	}
+	return buf.String(), nil
+}
+
+func nodeLinkEndpoint(keys map[string]int, raw json.RawMessage) (int, error) {
+	if raw == nil {
+		return 0, errors.New("missing")
+	}
+	key, err := nodeLinkKey(raw)
+	if err != nil {
+		return 0, err
+	}
+	index, ok := keys[key]
+	if !ok {
+		return 0, fmt.Errorf("unknown node %s", key)
+	}
+	return index, nil
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNodeLinkRoundTrip(t *testing.T) {
+	g := newTestGraph(3, [2]int{0, 1}, [2]int{1, 2})
+	g.ReplaceNodeValue(2, 42)
+
+	var buf bytes.Buffer
+	err := WriteNodeLink(&buf, g, func(node *Node[IntNodeValue]) map[string]string {
+		return map[string]string{"value": strconv.Itoa(int(node.Value)), "id": "ignored"}
+	})
+	if err != nil {
+		t.Fatalf("WriteNodeLink failed: %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("written document is not JSON: %v", err)
+	}
+	if doc["directed"] != false || doc["multigraph"] != false {
+		t.Errorf("document = %v, want an undirected simple graph", doc)
+	}
+	if links, ok := doc["links"].([]any); !ok || len(links) != 2 {
+		t.Errorf("links = %v, want 2 links", doc["links"])
+	}
+
+	read, err := ReadNodeLink(&buf, intValueFromAttrs)
+	if err != nil {
+		t.Fatalf("ReadNodeLink failed: %v", err)
+	}
+	if read.Fingerprint() != g.Fingerprint() {
+		t.Errorf("round trip changed the graph structure")
+	}
+	for i, node := range read.GetNodes() {
+		if node.Value != g.GetNodes()[i].Value {
+			t.Errorf("node %d value = %d, want %d", i, node.Value, g.GetNodes()[i].Value)
+		}
+	}
+}
+
+func TestNodeLinkEmptyGraph(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteNodeLink(&buf, NewGraph[IntNodeValue](), nil); err != nil {
+		t.Fatalf("WriteNodeLink failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"links":[]`) {
+		t.Errorf("empty graph has no links list: %s", buf.String())
+	}
+}
+
+func TestReadNodeLink(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		edges []Edge
+	}{
+		{
+			name: "links with string keys",
+			data: `{"directed": false, "multigraph": false, "graph": {},
+				"nodes": [{"id": "b", "value": 7}, {"id": "a", "value": "8"}, {"id": "c", "value": 9}],
+				"links": [{"source": "a", "target": "b"}, {"source": "c", "target": "a"}]}`,
+			edges: []Edge{{From: 1, To: 0}, {From: 2, To: 1}},
+		},
+		{
+			name: "edges with mixed keys",
+			data: `{"directed": false, "multigraph": false, "graph": {"name": "g"},
+				"nodes": [{"id": 10, "value": 7}, {"id": "ten", "value": 8}, {"id": [1, 2], "value": 9}],
+				"edges": [{"source": 10, "target": "ten"}, {"source": [1,2], "target": 10, "weight": 3}]}`,
+			edges: []Edge{{From: 0, To: 1}, {From: 2, To: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := ReadNodeLink(strings.NewReader(tt.data), intValueFromAttrs)
+			if err != nil {
+				t.Fatalf("ReadNodeLink failed: %v", err)
+			}
+
+			for i, expected := range []IntNodeValue{7, 8, 9} {
+				if name, _ := g.NodeName(i); name == "" {
+					t.Errorf("node %d has no name", i)
+				}
+				if value := g.GetNodes()[i].Value; value != expected {
+					t.Errorf("node %d value = %d, want %d", i, value, expected)
+				}
+			}
+			edges := g.GetEdges()
+			if len(edges) != len(tt.edges) {
+				t.Fatalf("edges = %v, want %v", edges, tt.edges)
+			}
+			for i, edge := range edges {
+				if edge != tt.edges[i] {
+					t.Errorf("edge %d = %v, want %v", i, edge, tt.edges[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadNodeLinkErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not JSON", data: "graph G {}"},
+		{name: "no nodes", data: `{"links": []}`},
+		{name: "node without id", data: `{"nodes": [{"value": 1}]}`},
+		{name: "null id", data: `{"nodes": [{"id": null, "value": 1}]}`},
+		{name: "duplicate id", data: `{"nodes": [{"id": "a", "value": 1}, {"id": "a", "value": 2}]}`},
+		{name: "unknown source", data: `{"nodes": [{"id": "a", "value": 1}], "links": [{"source": "b", "target": "a"}]}`},
+		{name: "missing target", data: `{"nodes": [{"id": "a", "value": 1}], "links": [{"source": "a"}]}`},
+		{name: "same name", data: `{"nodes": [{"id": 1, "value": 1}, {"id": "1", "value": 2}]}`},
+		{name: "links and edges", data: `{"nodes": [], "links": [], "edges": []}`},
+		{name: "invalid value", data: `{"nodes": [{"id": "a", "value": "x"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadNodeLink(strings.NewReader(tt.data), intValueFromAttrs); !errors.Is(err, ErrInvalidNodeLink) {
+				t.Errorf("ReadNodeLink error = %v, want ErrInvalidNodeLink", err)
+			}
+		})
+	}
+}
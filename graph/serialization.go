@@ -3,16 +3,22 @@ package graph
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
 )
 
-// Serialize the node into a byte array
+// Serialize the node into a byte array using the v1 node format
 // the format is as follows:
 // [id_size][id][value_size][value]
-func (n *Node[T]) Serialize() []byte {
+// It returns ErrTooLargeForV1 when the id or the value does not fit in 16 bits.
+func (n *Node[T]) Serialize() ([]byte, error) {
 	var buf bytes.Buffer
 
+	if n.Id < 0 || n.Id > math.MaxUint16 {
+		return nil, fmt.Errorf("%w: node id %d", ErrTooLargeForV1, n.Id)
+	}
+
 	// Write ID size (2 bytes for uint16)
 	idSize := make([]byte, 2)
 	binary.BigEndian.PutUint16(idSize, 2)
@@ -20,7 +26,7 @@ func (n *Node[T]) Serialize() []byte {
 
 	// Write ID value
 	idBytes := make([]byte, 2)
-	binary.BigEndian.PutUint16(idBytes, n.Id)
+	binary.BigEndian.PutUint16(idBytes, uint16(n.Id))
 	buf.Write(idBytes)
 
 	// Write value size (2 bytes for uint16)
@@ -28,7 +34,7 @@ func (n *Node[T]) Serialize() []byte {
 	valueSize := make([]byte, 2)
 
 	if len(valueBytes) > math.MaxUint16 {
-		panic("value size too large")
+		return nil, fmt.Errorf("%w: value of node %d too large", ErrTooLargeForV1, n.Id)
 	}
 
 	binary.BigEndian.PutUint16(valueSize, uint16(len(valueBytes)))
@@ -37,15 +43,20 @@ func (n *Node[T]) Serialize() []byte {
 	// Write value
 	buf.Write(valueBytes)
 
-	return buf.Bytes()
+	return buf.Bytes(), nil
 }
 
-// Serialize the edge into a byte array
+// Serialize the edge into a byte array using the v1 edge format
 // the format is as follows:
 // [from_size][from_value][to_size][to_value]
-func (e *Edge) Serialize() []byte {
+// It returns ErrTooLargeForV1 when an index does not fit in 16 bits.
+func (e *Edge) Serialize() ([]byte, error) {
 	var buf bytes.Buffer
 
+	if e.From < 0 || e.From > math.MaxUint16 || e.To < 0 || e.To > math.MaxUint16 {
+		return nil, fmt.Errorf("%w: edge (%d, %d)", ErrTooLargeForV1, e.From, e.To)
+	}
+
 	// Write from size (2 bytes for uint16)
 	fromSize := make([]byte, 2)
 	binary.BigEndian.PutUint16(fromSize, 2)
@@ -66,25 +77,67 @@ func (e *Edge) Serialize() []byte {
 	binary.BigEndian.PutUint16(toBytes, uint16(e.To))
 	buf.Write(toBytes)
 
+	return buf.Bytes(), nil
+}
+
+// Serialize the graph into a byte array using the v2 format
+// the format is as follows:
+// [version][nodes_count][node1_value_size][node1_value]...[edges_count][edge1_from][edge1_to]...
+// every count, size and index is an unsigned varint and node ids are implicit:
+// the id of a node is its position.
+func (g *Graph[T]) Serialize() []byte {
+	var buf bytes.Buffer
+
+	// Write version (1 byte)
+	buf.Write([]byte{versionV2})
+
+	// Serialize nodes
+	buf.Write(binary.AppendUvarint(nil, uint64(len(g.nodes))))
+	for _, node := range g.nodes {
+		valueBytes := node.Value.Serialize()
+		buf.Write(binary.AppendUvarint(nil, uint64(len(valueBytes))))
+		buf.Write(valueBytes)
+	}
+
+	// Serialize edges
+	buf.Write(binary.AppendUvarint(nil, uint64(len(g.edges))))
+	for _, edge := range g.edges {
+		buf.Write(binary.AppendUvarint(nil, uint64(edge.From)))
+		buf.Write(binary.AppendUvarint(nil, uint64(edge.To)))
+	}
+
 	return buf.Bytes()
 }
 
-// Serialize the graph into a byte array
+var ErrTooLargeForV1 = errors.New("graph too large for format v1")
+
+// SerializeV1 serializes the graph into the v1 format, for readers that do not
+// support v2 yet
 // the format is as follows:
 // [version][nodes_size][node1_size][node1_value][node2_size][node2_value]...[edges_size][edge1_from_size][edge1_from_value][edge1_to_size][edge1_to_value]...
-func (g *Graph[T]) Serialize() []byte {
+func (g *Graph[T]) SerializeV1() ([]byte, error) {
+	if len(g.nodes) > math.MaxUint16+1 {
+		return nil, fmt.Errorf("%w: %d nodes", ErrTooLargeForV1, len(g.nodes))
+	}
 	var buf bytes.Buffer
 
 	// Write version (1 byte)
-	buf.Write([]byte{version})
+	buf.Write([]byte{versionV1})
 
 	// Serialize nodes
 	var nodesBuffer bytes.Buffer
 	for _, node := range g.nodes {
-		nodesBuffer.Write(node.Serialize())
+		nodeBytes, err := node.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		nodesBuffer.Write(nodeBytes)
 	}
 
 	// Write nodes size (4 bytes for uint32 since it could be large)
+	if nodesBuffer.Len() > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: nodes take %d bytes", ErrTooLargeForV1, nodesBuffer.Len())
+	}
 	nodesSize := make([]byte, 4)
 	binary.BigEndian.PutUint32(nodesSize, uint32(nodesBuffer.Len()))
 	buf.Write(nodesSize)
@@ -92,68 +145,116 @@ func (g *Graph[T]) Serialize() []byte {
 
 	// Serialize edges
 	var edgesBuffer bytes.Buffer
-	for _, edge := range g.edges {
-		edgesBuffer.Write(edge.Serialize())
+	for i, edge := range g.edges {
+		edgeBytes, err := edge.Serialize()
+		if err != nil {
+			return nil, fmt.Errorf("edge %d: %w", i, err)
+		}
+		edgesBuffer.Write(edgeBytes)
 	}
 
 	// Write edges size (4 bytes for uint32 since it could be large)
+	if edgesBuffer.Len() > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: edges take %d bytes", ErrTooLargeForV1, edgesBuffer.Len())
+	}
 	edgesSize := make([]byte, 4)
 	binary.BigEndian.PutUint32(edgesSize, uint32(edgesBuffer.Len()))
 	buf.Write(edgesSize)
 	buf.Write(edgesBuffer.Bytes())
 
-	return buf.Bytes()
+	return buf.Bytes(), nil
+}
+
+// DecodeOptions limits the resources a decoded graph can use.
+// A zero field means no limit.
+type DecodeOptions struct {
+	MaxNodes     int
+	MaxEdges     int
+	MaxValueSize int
+}
+
+var (
+	ErrTruncatedData = errors.New("data is truncated")
+	ErrInvalidData   = errors.New("invalid data")
+	ErrLimitExceeded = errors.New("decode limit exceeded")
+)
+
+// DecodeError reports where in the input decoding failed.
+type DecodeError struct {
+	Offset int
+	Err    error
 }
 
-// DeserializeNode creates a Node from a byte array
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func decodeErrorf(offset int, err error, format string, args ...any) error {
+	return &DecodeError{Offset: offset, Err: fmt.Errorf("%w: %s", err, fmt.Sprintf(format, args...))}
+}
+
+// DeserializeNode creates a Node from a byte array in the v1 node format
 // the format is as follows:
 // [id_size][id][value_size][value]
 func DeserializeNode[T NodeValue](data []byte, valueDeserializer func([]byte) (T, error)) (*Node[T], uint, error) {
-	totalSize := uint(0)
+	return deserializeNode(data, 0, valueDeserializer, DecodeOptions{})
+}
 
-	if len(data) < 8 { // Minimum size for a node (2+2+2+2)
-		return nil, 0, fmt.Errorf("data too short for node")
+func deserializeNode[T NodeValue](data []byte, offset int, valueDeserializer func([]byte) (T, error), opts DecodeOptions) (*Node[T], uint, error) {
+	if len(data) < 6 { // Minimum size for a node (2+2+2)
+		return nil, 0, decodeErrorf(offset, ErrTruncatedData, "data too short for node")
 	}
 
 	// Read ID size
 	idSize := binary.BigEndian.Uint16(data[0:2])
 	if idSize != 2 {
-		return nil, 0, fmt.Errorf("invalid id size: %d", idSize)
+		return nil, 0, decodeErrorf(offset, ErrInvalidData, "invalid id size: %d", idSize)
 	}
-	totalSize += 2
 
 	// Read ID value
-	id := binary.BigEndian.Uint16(data[2:4])
-	totalSize += 2
+	id := int(binary.BigEndian.Uint16(data[2:4]))
 
 	// Read value size
-	valueSize := binary.BigEndian.Uint16(data[4:6])
-	totalSize += 2
+	valueSize := int(binary.BigEndian.Uint16(data[4:6]))
+	if opts.MaxValueSize > 0 && valueSize > opts.MaxValueSize {
+		return nil, 0, decodeErrorf(offset+4, ErrLimitExceeded, "value size %d above %d", valueSize, opts.MaxValueSize)
+	}
+	if len(data) < 6+valueSize {
+		return nil, 0, decodeErrorf(offset+6, ErrTruncatedData, "value needs %d bytes, have %d", valueSize, len(data)-6)
+	}
+
 	// Read value
 	valueBytes := data[6 : 6+valueSize]
 	value, err := valueDeserializer(valueBytes)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to deserialize node value: %w", err)
+		return nil, 0, &DecodeError{Offset: offset + 6, Err: fmt.Errorf("failed to deserialize node value: %w", err)}
 	}
-	totalSize += uint(len(valueBytes))
 	return &Node[T]{
 		Id:    id,
-		Value: T(value),
-	}, totalSize, nil
+		Value: value,
+	}, uint(6 + valueSize), nil
 }
 
-// DeserializeEdge creates an Edge from a byte array
+// DeserializeEdge creates an Edge from a byte array in the v1 edge format
 // the format is as follows:
 // [from_size][from_value][to_size][to_value]
 func DeserializeEdge(data []byte) (*Edge, error) {
+	return deserializeEdge(data, 0)
+}
+
+func deserializeEdge(data []byte, offset int) (*Edge, error) {
 	if len(data) < 8 { // Minimum size for an edge (2+2+2+2)
-		return nil, fmt.Errorf("data too short for edge")
+		return nil, decodeErrorf(offset, ErrTruncatedData, "data too short for edge")
 	}
 
 	// Read from size
 	fromSize := binary.BigEndian.Uint16(data[0:2])
 	if fromSize != 2 {
-		return nil, fmt.Errorf("invalid from size: %d", fromSize)
+		return nil, decodeErrorf(offset, ErrInvalidData, "invalid from size: %d", fromSize)
 	}
 
 	// Read from value
@@ -162,7 +263,7 @@ func DeserializeEdge(data []byte) (*Edge, error) {
 	// Read to size
 	toSize := binary.BigEndian.Uint16(data[4:6])
 	if toSize != 2 {
-		return nil, fmt.Errorf("invalid to size: %d", toSize)
+		return nil, decodeErrorf(offset+4, ErrInvalidData, "invalid to size: %d", toSize)
 	}
 
 	// Read to value
@@ -174,63 +275,222 @@ func DeserializeEdge(data []byte) (*Edge, error) {
 	}, nil
 }
 
-// DeserializeGraph creates a Graph from a byte array
-// the format is as follows:
-// [version][nodes_size][node1_size][node1_value][node2_size][node2_value]...[edges_size][edge1_from_size][edge1_from_value][edge1_to_size][edge1_to_value]...
+// DeserializeGraph creates a Graph from a byte array in the v1 or v2 format,
+// depending on its version byte.
 func DeserializeGraph[T NodeValue](data []byte, valueDeserializer func([]byte) (T, error)) (*Graph[T], error) {
-	if len(data) < 9 { // Minimum size for a graph (1+4+4)
-		return nil, fmt.Errorf("data too short for graph")
+	return DeserializeGraphWithOptions(data, valueDeserializer, DecodeOptions{})
+}
+
+// DeserializeGraphWithOptions is like DeserializeGraph but enforces the limits
+// of opts. It never panics on malformed input: errors are *DecodeError values
+// with the offset of the problem, wrapping ErrTruncatedData, ErrInvalidData or
+// ErrLimitExceeded. Node ids must be sequential, every edge must connect
+// existing nodes and there must be no data after the edges.
+func DeserializeGraphWithOptions[T NodeValue](data []byte, valueDeserializer func([]byte) (T, error), opts DecodeOptions) (*Graph[T], error) {
+	if len(data) < 1 {
+		return nil, decodeErrorf(0, ErrTruncatedData, "data too short for graph")
 	}
 
 	// Read version
-	if data[0] != version {
-		return nil, fmt.Errorf("invalid version: %d", data[0])
+	switch data[0] {
+	case versionV1:
+		return deserializeGraphV1(data, valueDeserializer, opts)
+	case versionV2:
+		return deserializeGraphV2(data, valueDeserializer, opts)
+	default:
+		return nil, decodeErrorf(0, ErrInvalidData, "invalid version: %d", data[0])
+	}
+}
+
+// deserializeGraphV1 reads the v1 format
+// the format is as follows:
+// [version][nodes_size][node1_size][node1_value][node2_size][node2_value]...[edges_size][edge1_from_size][edge1_from_value][edge1_to_size][edge1_to_value]...
+func deserializeGraphV1[T NodeValue](data []byte, valueDeserializer func([]byte) (T, error), opts DecodeOptions) (*Graph[T], error) {
+	if len(data) < 9 { // Minimum size for a graph (1+4+4)
+		return nil, decodeErrorf(0, ErrTruncatedData, "data too short for graph")
 	}
 
 	// Read nodes size
-	nodesSize := binary.BigEndian.Uint32(data[1:5])
-	if len(data) < int(5+nodesSize) {
-		return nil, fmt.Errorf("data too short for nodes")
+	rawNodesSize := binary.BigEndian.Uint32(data[1:5])
+	if uint64(len(data)-5) < uint64(rawNodesSize) {
+		return nil, decodeErrorf(1, ErrTruncatedData, "nodes need %d bytes, have %d", rawNodesSize, len(data)-5)
 	}
+	nodesSize := int(rawNodesSize)
 
 	// Deserialize nodes
 	nodesData := data[5 : 5+nodesSize]
 	nodes := make([]*Node[T], 0)
 	offset := 0
 	for offset < len(nodesData) {
-		node, totalSize, err := DeserializeNode(nodesData[offset:], valueDeserializer)
+		if opts.MaxNodes > 0 && len(nodes) >= opts.MaxNodes {
+			return nil, decodeErrorf(5+offset, ErrLimitExceeded, "more than %d nodes", opts.MaxNodes)
+		}
+
+		node, totalSize, err := deserializeNode(nodesData[offset:], 5+offset, valueDeserializer, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to deserialize node: %w", err)
 		}
+		if node.Id != len(nodes) {
+			return nil, decodeErrorf(5+offset+2, ErrInvalidData, "node id %d at index %d", node.Id, len(nodes))
+		}
 		nodes = append(nodes, node)
 		offset += int(totalSize)
 	}
 
 	// Read edges size
 	edgesOffset := 5 + nodesSize
-	if len(data) < int(edgesOffset+4) {
-		return nil, fmt.Errorf("data too short for edges size")
+	if len(data)-edgesOffset < 4 {
+		return nil, decodeErrorf(edgesOffset, ErrTruncatedData, "data too short for edges size")
 	}
-	edgesSize := binary.BigEndian.Uint32(data[edgesOffset : edgesOffset+4])
+	rawEdgesSize := binary.BigEndian.Uint32(data[edgesOffset : edgesOffset+4])
 
 	// Deserialize edges
-	if len(data) < int(edgesOffset+4+edgesSize) {
-		return nil, fmt.Errorf("data too short for edges")
-	}
-	edgesData := data[edgesOffset+4 : edgesOffset+4+edgesSize]
-	edges := make([]Edge, 0)
-	offset = 0
-	for offset < len(edgesData) {
-		edge, err := DeserializeEdge(edgesData[offset:])
+	edgesStart := edgesOffset + 4
+	if uint64(len(data)-edgesStart) < uint64(rawEdgesSize) {
+		return nil, decodeErrorf(edgesOffset, ErrTruncatedData, "edges need %d bytes, have %d", rawEdgesSize, len(data)-edgesStart)
+	}
+	edgesSize := int(rawEdgesSize)
+	if len(data)-edgesStart > edgesSize {
+		return nil, decodeErrorf(edgesStart+edgesSize, ErrInvalidData, "%d bytes after the edges", len(data)-edgesStart-edgesSize)
+	}
+	if edgesSize%8 != 0 { // Each edge is 8 bytes
+		return nil, decodeErrorf(edgesOffset, ErrInvalidData, "edges size %d is not a multiple of 8", edgesSize)
+	}
+	if opts.MaxEdges > 0 && edgesSize/8 > opts.MaxEdges {
+		return nil, decodeErrorf(edgesOffset, ErrLimitExceeded, "%d edges above %d", edgesSize/8, opts.MaxEdges)
+	}
+
+	edgesData := data[edgesStart : edgesStart+edgesSize]
+	edges := make([]Edge, 0, edgesSize/8)
+	for offset = 0; offset < len(edgesData); offset += 8 {
+		edge, err := deserializeEdge(edgesData[offset:], edgesStart+offset)
 		if err != nil {
 			return nil, fmt.Errorf("failed to deserialize edge: %w", err)
 		}
+		if edge.From >= len(nodes) || edge.To >= len(nodes) {
+			return nil, decodeErrorf(edgesStart+offset, ErrInvalidData, "edge (%d, %d) with %d nodes", edge.From, edge.To, len(nodes))
+		}
 		edges = append(edges, *edge)
-		offset += 8 // Each edge is 8 bytes
 	}
 
-	return &Graph[T]{
-		nodes: nodes,
-		edges: edges,
-	}, nil
+	return newGraphFrom(nodes, edges), nil
+}
+
+// deserializeGraphV2 reads the v2 format
+// the format is as follows:
+// [version][nodes_count][node1_value_size][node1_value]...[edges_count][edge1_from][edge1_to]...
+func deserializeGraphV2[T NodeValue](data []byte, valueDeserializer func([]byte) (T, error), opts DecodeOptions) (*Graph[T], error) {
+	d := &varintDecoder{data: data, offset: 1}
+
+	// Read nodes count, every node takes at least one byte
+	nodesOffset := d.offset
+	nodesCount, err := d.count("nodes count", 1)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxNodes > 0 && nodesCount > opts.MaxNodes {
+		return nil, decodeErrorf(nodesOffset, ErrLimitExceeded, "%d nodes above %d", nodesCount, opts.MaxNodes)
+	}
+
+	// Deserialize nodes
+	nodes := make([]*Node[T], nodesCount)
+	for i := range nodes {
+		valueOffset := d.offset
+		valueSize, err := d.count("value size", 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize node: %w", err)
+		}
+		if opts.MaxValueSize > 0 && valueSize > opts.MaxValueSize {
+			return nil, decodeErrorf(valueOffset, ErrLimitExceeded, "value size %d above %d", valueSize, opts.MaxValueSize)
+		}
+
+		value, err := valueDeserializer(d.data[d.offset : d.offset+valueSize])
+		if err != nil {
+			return nil, &DecodeError{Offset: d.offset, Err: fmt.Errorf("failed to deserialize node value: %w", err)}
+		}
+		d.offset += valueSize
+
+		nodes[i] = &Node[T]{Id: i, Value: value}
+	}
+
+	// Read edges count, every edge takes at least two bytes
+	edgesOffset := d.offset
+	edgesCount, err := d.count("edges count", 2)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxEdges > 0 && edgesCount > opts.MaxEdges {
+		return nil, decodeErrorf(edgesOffset, ErrLimitExceeded, "%d edges above %d", edgesCount, opts.MaxEdges)
+	}
+
+	// Deserialize edges
+	edges := make([]Edge, edgesCount)
+	for i := range edges {
+		edgeOffset := d.offset
+		from, err := d.index(len(nodes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize edge: %w", err)
+		}
+		to, err := d.index(len(nodes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize edge: %w", err)
+		}
+		if from >= len(nodes) || to >= len(nodes) {
+			return nil, decodeErrorf(edgeOffset, ErrInvalidData, "edge (%d, %d) with %d nodes", from, to, len(nodes))
+		}
+		edges[i] = Edge{From: from, To: to}
+	}
+
+	if d.offset != len(data) {
+		return nil, decodeErrorf(d.offset, ErrInvalidData, "%d bytes after the edges", len(data)-d.offset)
+	}
+
+	return newGraphFrom(nodes, edges), nil
+}
+
+// varintDecoder reads unsigned varints from a byte array and reports errors
+// with their offset instead of reading past its end.
+type varintDecoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *varintDecoder) uvarint(what string) (uint64, error) {
+	value, n := binary.Uvarint(d.data[d.offset:])
+	if n == 0 {
+		return 0, decodeErrorf(d.offset, ErrTruncatedData, "data too short for %s", what)
+	}
+	if n < 0 {
+		return 0, decodeErrorf(d.offset, ErrInvalidData, "%s overflows", what)
+	}
+	d.offset += n
+	return value, nil
+}
+
+// count reads a number of items that each take at least itemSize bytes, and
+// fails if that many items can not fit in the remaining data.
+func (d *varintDecoder) count(what string, itemSize int) (int, error) {
+	offset := d.offset
+	value, err := d.uvarint(what)
+	if err != nil {
+		return 0, err
+	}
+	remaining := uint64(len(d.data) - d.offset)
+	if value > remaining/uint64(itemSize) {
+		return 0, decodeErrorf(offset, ErrTruncatedData, "%s %d does not fit in %d bytes", what, value, remaining)
+	}
+	return int(value), nil
+}
+
+// index reads a node index. Indices of at least nodesCount are returned as
+// nodesCount so the caller can report the edge.
+func (d *varintDecoder) index(nodesCount int) (int, error) {
+	value, err := d.uvarint("node index")
+	if err != nil {
+		return 0, err
+	}
+	if value >= uint64(nodesCount) {
+		return nodesCount, nil
+	}
+	return int(value), nil
 }
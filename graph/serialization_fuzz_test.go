@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func FuzzDeserializeGraph(f *testing.F) {
+	empty := NewGraph[IntNodeValue]()
+	f.Add(empty.Serialize())
+
+	path := NewGraph[IntNodeValue]()
+	for i := range 4 {
+		path.AddNode(IntNodeValue(i))
+	}
+	path.AddEdge(0, 1)
+	path.AddEdge(1, 2)
+	path.AddEdge(2, 3)
+	f.Add(path.Serialize())
+	if v1, err := path.SerializeV1(); err == nil {
+		f.Add(v1)
+	}
+
+	opts := DecodeOptions{MaxNodes: 1 << 10, MaxEdges: 1 << 12, MaxValueSize: 64}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, err := DeserializeGraphWithOptions(data, DeserializeIntNodeValue, opts)
+		if err != nil {
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("error %v is not a *DecodeError", err)
+			}
+			return
+		}
+
+		// Whatever is accepted must be a valid graph that survives a round trip
+		for i, node := range g.nodes {
+			if int(node.Id) != i {
+				t.Fatalf("node %d has id %d", i, node.Id)
+			}
+		}
+		for _, edge := range g.edges {
+			if edge.From >= len(g.nodes) || edge.To >= len(g.nodes) {
+				t.Fatalf("edge (%d, %d) with %d nodes", edge.From, edge.To, len(g.nodes))
+			}
+		}
+
+		serialized := g.Serialize()
+		again, err := DeserializeGraphWithOptions(serialized, DeserializeIntNodeValue, opts)
+		if err != nil {
+			t.Fatalf("round trip failed: %v", err)
+		}
+		if !bytes.Equal(serialized, again.Serialize()) {
+			t.Fatal("round trip changed the graph")
+		}
+	})
+}
+
+func FuzzDeserializeNode(f *testing.F) {
+	node := Node[IntNodeValue]{Id: 1, Value: 42}
+	seed, _ := node.Serialize()
+	f.Add(seed)
+	f.Add([]byte{0, 2, 0, 1, 0xFF, 0xFF})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		node, size, err := DeserializeNode(data, DeserializeIntNodeValue)
+		if err != nil {
+			return
+		}
+		if int(size) > len(data) {
+			t.Fatalf("node size %d larger than the %d bytes of input", size, len(data))
+		}
+
+		// Value deserializers may ignore extra bytes, so compare the decoded nodes
+		serialized, err := node.Serialize()
+		if err != nil {
+			t.Fatalf("serialization failed: %v", err)
+		}
+		again, _, err := DeserializeNode(serialized, DeserializeIntNodeValue)
+		if err != nil {
+			t.Fatalf("round trip failed: %v", err)
+		}
+		if again.Id != node.Id || again.Value != node.Value {
+			t.Fatal("round trip changed the node")
+		}
+	})
+}
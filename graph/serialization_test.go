@@ -1,6 +1,9 @@
 package graph
 
 import (
+	"bytes"
+	"errors"
+	"math"
 	"testing"
 )
 
@@ -40,7 +43,10 @@ func TestNodeSerialization(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			serialized := tt.node.Serialize()
+			serialized, err := tt.node.Serialize()
+			if err != nil {
+				t.Fatalf("serialization failed: %v", err)
+			}
 			if len(serialized) != len(tt.expected) {
 				t.Errorf("serialized length = %v, want %v", len(serialized), len(tt.expected))
 			}
@@ -101,7 +107,10 @@ func TestEdgeSerialization(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			serialized := tt.edge.Serialize()
+			serialized, err := tt.edge.Serialize()
+			if err != nil {
+				t.Fatalf("serialization failed: %v", err)
+			}
 			if len(serialized) != len(tt.expected) {
 				t.Errorf("serialized length = %v, want %v", len(serialized), len(tt.expected))
 			}
@@ -136,7 +145,138 @@ func TestGraphSerialization(t *testing.T) {
 			name:  "empty graph",
 			graph: NewGraph[IntNodeValue](),
 			expected: []byte{
-				version,    // version
+				versionV2, // version
+				0,         // nodes count (0)
+				0,         // edges count (0)
+			},
+		},
+		{
+			name: "graph with one node",
+			graph: func() *Graph[IntNodeValue] {
+				g := NewGraph[IntNodeValue]()
+				g.AddNode(42)
+				return g
+			}(),
+			expected: []byte{
+				versionV2, // version
+				1,         // nodes count (1)
+				2, 0, 42,  // node data
+				0, // edges count (0)
+			},
+		},
+		{
+			name: "graph with one edge",
+			graph: func() *Graph[IntNodeValue] {
+				g := NewGraph[IntNodeValue]()
+				g.AddNode(42)
+				g.AddNode(43)
+				g.AddEdge(0, 1)
+				return g
+			}(),
+			expected: []byte{
+				versionV2, // version
+				2,         // nodes count (2)
+				2, 0, 42,  // node 1
+				2, 0, 43, // node 2
+				1,    // edges count (1)
+				0, 1, // edge data
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serialized := tt.graph.Serialize()
+			if !bytes.Equal(serialized, tt.expected) {
+				t.Errorf("serialized = %v, want %v", serialized, tt.expected)
+			}
+
+			deserialized, err := DeserializeGraph(serialized, DeserializeIntNodeValue)
+			if err != nil {
+				t.Fatalf("deserialization failed: %v", err)
+			}
+			if len(deserialized.nodes) != len(tt.graph.nodes) {
+				t.Errorf("deserialized nodes length = %v, want %v", len(deserialized.nodes), len(tt.graph.nodes))
+			}
+			if len(deserialized.edges) != len(tt.graph.edges) {
+				t.Errorf("deserialized edges length = %v, want %v", len(deserialized.edges), len(tt.graph.edges))
+			}
+		})
+	}
+}
+
+func TestGraphSerializationLargeGraph(t *testing.T) {
+	const nodes = math.MaxUint16 + 10
+
+	g := NewGraph[IntNodeValue]()
+	for i := range nodes {
+		g.AddNode(IntNodeValue(i % 100))
+	}
+	for i := 1; i < nodes; i++ {
+		g.AddEdge(i-1, i)
+	}
+
+	if _, err := g.SerializeV1(); !errors.Is(err, ErrTooLargeForV1) {
+		t.Errorf("SerializeV1 error = %v, want ErrTooLargeForV1", err)
+	}
+
+	// The nodes and edges of the large graph do not fit in format v1 either
+	last := g.GetNodes()[nodes-1]
+	if _, err := last.Serialize(); !errors.Is(err, ErrTooLargeForV1) {
+		t.Errorf("Node.Serialize error = %v, want ErrTooLargeForV1", err)
+	}
+	edge := g.GetEdges()[nodes-2]
+	if _, err := edge.Serialize(); !errors.Is(err, ErrTooLargeForV1) {
+		t.Errorf("Edge.Serialize error = %v, want ErrTooLargeForV1", err)
+	}
+
+	deserialized, err := DeserializeGraph(g.Serialize(), DeserializeIntNodeValue)
+	if err != nil {
+		t.Fatalf("deserialization failed: %v", err)
+	}
+	if len(deserialized.nodes) != nodes {
+		t.Fatalf("deserialized %d nodes, want %d", len(deserialized.nodes), nodes)
+	}
+	if last := deserialized.nodes[nodes-1]; last.Id != nodes-1 || last.Value != IntNodeValue((nodes-1)%100) {
+		t.Errorf("last node = %+v, want id %d", *last, nodes-1)
+	}
+	if last := deserialized.edges[len(deserialized.edges)-1]; last.From != nodes-2 || last.To != nodes-1 {
+		t.Errorf("last edge = %+v, want (%d, %d)", last, nodes-2, nodes-1)
+	}
+}
+
+func TestDeserializeGraphReadsV1(t *testing.T) {
+	g := NewGraph[IntNodeValue]()
+	g.AddNode(42)
+	g.AddNode(43)
+	g.AddNode(44)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+
+	v1, err := g.SerializeV1()
+	if err != nil {
+		t.Fatalf("SerializeV1 failed: %v", err)
+	}
+	deserialized, err := DeserializeGraph(v1, DeserializeIntNodeValue)
+	if err != nil {
+		t.Fatalf("deserialization failed: %v", err)
+	}
+	if !bytes.Equal(deserialized.Serialize(), g.Serialize()) {
+		t.Errorf("graph read from v1 differs from the original")
+	}
+}
+
+func TestGraphSerializationV1(t *testing.T) {
+	tests := []struct {
+		name     string
+		graph    *Graph[IntNodeValue]
+		expected []byte
+	}{
+		{
+			name:  "empty graph",
+			graph: NewGraph[IntNodeValue](),
+			expected: []byte{
+				versionV1,  // version
 				0, 0, 0, 0, // nodes size (0)
 				0, 0, 0, 0, // edges size (0)
 			},
@@ -149,7 +289,7 @@ func TestGraphSerialization(t *testing.T) {
 				return g
 			}(),
 			expected: []byte{
-				version,    // version
+				versionV1,  // version
 				0, 0, 0, 8, // nodes size (8)
 				0, 2, 0, 0, 0, 2, 0, 42, // node data
 				0, 0, 0, 0, // edges size (0)
@@ -165,7 +305,7 @@ func TestGraphSerialization(t *testing.T) {
 				return g
 			}(),
 			expected: []byte{
-				version,     // version
+				versionV1,   // version
 				0, 0, 0, 16, // nodes size (16)
 				0, 2, 0, 0, 0, 2, 0, 42, // node 1
 				0, 2, 0, 1, 0, 2, 0, 43, // node 2
@@ -177,7 +317,10 @@ func TestGraphSerialization(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			serialized := tt.graph.Serialize()
+			serialized, err := tt.graph.SerializeV1()
+			if err != nil {
+				t.Fatalf("serialization failed: %v", err)
+			}
 			if len(serialized) != len(tt.expected) {
 				t.Errorf("serialized length = %v, want %v", len(serialized), len(tt.expected))
 			}
@@ -201,3 +344,263 @@ func TestGraphSerialization(t *testing.T) {
 		})
 	}
 }
+
+func TestDeserializeGraphV1Errors(t *testing.T) {
+	valid := func() []byte {
+		g := NewGraph[IntNodeValue]()
+		g.AddNode(42)
+		g.AddNode(43)
+		g.AddEdge(0, 1)
+		data, _ := g.SerializeV1()
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		opts     DecodeOptions
+		expected error
+		offset   int
+	}{
+		{
+			name:     "too short",
+			data:     []byte{versionV1, 0, 0},
+			expected: ErrTruncatedData,
+			offset:   0,
+		},
+		{
+			name:     "invalid version",
+			data:     append([]byte{0xFF}, valid()[1:]...),
+			expected: ErrInvalidData,
+			offset:   0,
+		},
+		{
+			name:     "nodes size overflow",
+			data:     append([]byte{versionV1, 0xFF, 0xFF, 0xFF, 0xFF}, valid()[5:]...),
+			expected: ErrTruncatedData,
+			offset:   1,
+		},
+		{
+			name: "value size past the end",
+			data: []byte{
+				versionV1,
+				0, 0, 0, 8,
+				0, 2, 0, 0, 0, 200, 0, 42, // value size (200)
+				0, 0, 0, 0,
+			},
+			expected: ErrTruncatedData,
+			offset:   11,
+		},
+		{
+			name: "node ids not sequential",
+			data: []byte{
+				versionV1,
+				0, 0, 0, 16,
+				0, 2, 0, 0, 0, 2, 0, 42,
+				0, 2, 0, 0, 0, 2, 0, 43, // id (0) again
+				0, 0, 0, 0,
+			},
+			expected: ErrInvalidData,
+			offset:   15,
+		},
+		{
+			name: "dangling edge",
+			data: []byte{
+				versionV1,
+				0, 0, 0, 8,
+				0, 2, 0, 0, 0, 2, 0, 42,
+				0, 0, 0, 8,
+				0, 2, 0, 0, 0, 2, 0, 1, // to (1) does not exist
+			},
+			expected: ErrInvalidData,
+			offset:   17,
+		},
+		{
+			name: "partial edge",
+			data: []byte{
+				versionV1,
+				0, 0, 0, 0,
+				0, 0, 0, 4,
+				0, 2, 0, 0,
+			},
+			expected: ErrInvalidData,
+			offset:   5,
+		},
+		{
+			name:     "trailing data",
+			data:     append(valid(), 0),
+			expected: ErrInvalidData,
+			offset:   len(valid()),
+		},
+		{
+			name:     "too many nodes",
+			data:     valid(),
+			opts:     DecodeOptions{MaxNodes: 1},
+			expected: ErrLimitExceeded,
+			offset:   13,
+		},
+		{
+			name:     "value too large",
+			data:     valid(),
+			opts:     DecodeOptions{MaxValueSize: 1},
+			expected: ErrLimitExceeded,
+			offset:   9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DeserializeGraphWithOptions(tt.data, DeserializeIntNodeValue, tt.opts)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("error = %v, want %v", err, tt.expected)
+			}
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("error %v is not a *DecodeError", err)
+			}
+			if decodeErr.Offset != tt.offset {
+				t.Errorf("offset = %d, want %d", decodeErr.Offset, tt.offset)
+			}
+		})
+	}
+}
+
+func TestDeserializeGraphV2Errors(t *testing.T) {
+	valid := func() []byte {
+		g := NewGraph[IntNodeValue]()
+		g.AddNode(42)
+		g.AddNode(43)
+		g.AddEdge(0, 1)
+		return g.Serialize()
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		opts     DecodeOptions
+		expected error
+		offset   int
+	}{
+		{
+			name:     "missing nodes count",
+			data:     []byte{versionV2},
+			expected: ErrTruncatedData,
+			offset:   1,
+		},
+		{
+			name:     "varint overflow",
+			data:     []byte{versionV2, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01},
+			expected: ErrInvalidData,
+			offset:   1,
+		},
+		{
+			name:     "nodes count past the end",
+			data:     []byte{versionV2, 0xFF, 0xFF, 0x03, 0, 0},
+			expected: ErrTruncatedData,
+			offset:   1,
+		},
+		{
+			name:     "value size past the end",
+			data:     []byte{versionV2, 1, 200, 0, 42, 0},
+			expected: ErrTruncatedData,
+			offset:   2,
+		},
+		{
+			name:     "missing edges count",
+			data:     []byte{versionV2, 1, 2, 0, 42},
+			expected: ErrTruncatedData,
+			offset:   5,
+		},
+		{
+			name:     "dangling edge",
+			data:     []byte{versionV2, 1, 2, 0, 42, 1, 0, 1}, // to (1) does not exist
+			expected: ErrInvalidData,
+			offset:   6,
+		},
+		{
+			name:     "partial edge",
+			data:     []byte{versionV2, 0, 1, 0},
+			expected: ErrTruncatedData,
+			offset:   2,
+		},
+		{
+			name:     "trailing data",
+			data:     append(valid(), 0),
+			expected: ErrInvalidData,
+			offset:   len(valid()),
+		},
+		{
+			name:     "too many nodes",
+			data:     valid(),
+			opts:     DecodeOptions{MaxNodes: 1},
+			expected: ErrLimitExceeded,
+			offset:   1,
+		},
+		{
+			name:     "value too large",
+			data:     valid(),
+			opts:     DecodeOptions{MaxValueSize: 1},
+			expected: ErrLimitExceeded,
+			offset:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DeserializeGraphWithOptions(tt.data, DeserializeIntNodeValue, tt.opts)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("error = %v, want %v", err, tt.expected)
+			}
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("error %v is not a *DecodeError", err)
+			}
+			if decodeErr.Offset != tt.offset {
+				t.Errorf("offset = %d, want %d", decodeErr.Offset, tt.offset)
+			}
+		})
+	}
+}
+
+func TestDeserializeGraphLimits(t *testing.T) {
+	g := NewGraph[IntNodeValue]()
+	g.AddNode(1)
+	g.AddNode(2)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 0)
+
+	opts := DecodeOptions{MaxNodes: 2, MaxEdges: 2, MaxValueSize: 2}
+	if _, err := DeserializeGraphWithOptions(g.Serialize(), DeserializeIntNodeValue, opts); err != nil {
+		t.Errorf("graph within the limits failed: %v", err)
+	}
+
+	opts.MaxEdges = 1
+	if _, err := DeserializeGraphWithOptions(g.Serialize(), DeserializeIntNodeValue, opts); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("error = %v, want ErrLimitExceeded", err)
+	}
+}
+
+func TestDeserializeGraphEmptyValues(t *testing.T) {
+	g := NewGraph[emptyNodeValue]()
+	g.AddNode(emptyNodeValue{})
+	g.AddNode(emptyNodeValue{})
+	g.AddEdge(0, 1)
+
+	deserialized, err := DeserializeGraph(g.Serialize(), func([]byte) (emptyNodeValue, error) {
+		return emptyNodeValue{}, nil
+	})
+	if err != nil {
+		t.Fatalf("deserialization failed: %v", err)
+	}
+	if len(deserialized.nodes) != 2 || len(deserialized.edges) != 1 {
+		t.Errorf("deserialized %d nodes and %d edges, want 2 and 1", len(deserialized.nodes), len(deserialized.edges))
+	}
+}
+
+type emptyNodeValue struct{}
+
+func (emptyNodeValue) Serialize() []byte {
+	return nil
+}
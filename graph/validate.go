@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNodeIdMismatch = errors.New("node id does not match its index")
+	ErrDanglingEdge   = errors.New("edge points to a missing node")
+	ErrSelfLoop       = errors.New("edge connects a node to itself")
+	ErrDuplicateEdge  = errors.New("duplicate edge")
+)
+
+// Validate reports every structural problem of the graph: nodes whose id is
+// not their index, edges to missing nodes, self-loops and edges that repeat an
+// earlier edge in either direction. The problems are joined with errors.Join
+// and each one wraps one of the Err* sentinels above. A valid graph returns nil.
+func (g *Graph[T]) Validate() error {
+	var errs []error
+
+	for i, node := range g.nodes {
+		if node.Id != i {
+			errs = append(errs, fmt.Errorf("%w: node %d at index %d", ErrNodeIdMismatch, node.Id, i))
+		}
+	}
+
+	seen := make(map[[2]int]int, len(g.edges))
+	for i, edge := range g.edges {
+		if edge.From < 0 || edge.From >= len(g.nodes) || edge.To < 0 || edge.To >= len(g.nodes) {
+			errs = append(errs, fmt.Errorf("%w: edge %d is (%d, %d) with %d nodes", ErrDanglingEdge, i, edge.From, edge.To, len(g.nodes)))
+		}
+		if edge.From == edge.To {
+			errs = append(errs, fmt.Errorf("%w: edge %d is (%d, %d)", ErrSelfLoop, i, edge.From, edge.To))
+		}
+
+		key := canonicalEdge(edge.From, edge.To)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("%w: edge %d (%d, %d) repeats edge %d", ErrDuplicateEdge, i, edge.From, edge.To, first))
+			continue
+		}
+		seen[key] = i
+	}
+
+	return errors.Join(errs...)
+}
+
+// Normalize rewrites every edge with its smaller index first and removes the
+// edges that repeat an earlier one, keeping the order of the remaining edges.
+// It returns the number of removed edges. Self-loops and dangling edges are
+// kept, use Validate to find them.
+func (g *Graph[T]) Normalize() int {
+	seen := make(map[[2]int]struct{}, len(g.edges))
+	edges := make([]Edge, 0, len(g.edges))
+	for _, edge := range g.edges {
+		key := canonicalEdge(edge.From, edge.To)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		edge.From, edge.To = key[0], key[1]
+		edges = append(edges, edge)
+	}
+
+	removed := len(g.edges) - len(edges)
+	g.edges = edges
+	g.adjacency = make([][]int, len(g.nodes))
+	for _, edge := range g.edges {
+		g.indexEdge(edge.From, edge.To)
+	}
+	return removed
+}
+
+func canonicalEdge(a, b int) [2]int {
+	return [2]int{min(a, b), max(a, b)}
+}
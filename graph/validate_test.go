@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		graph    func() *Graph[IntNodeValue]
+		expected []error
+	}{
+		{
+			name:  "valid graph",
+			graph: func() *Graph[IntNodeValue] { return newTestGraph(3, [2]int{0, 1}, [2]int{1, 2}) },
+		},
+		{
+			name:  "empty graph",
+			graph: func() *Graph[IntNodeValue] { return NewGraph[IntNodeValue]() },
+		},
+		{
+			name:     "dangling edge",
+			graph:    func() *Graph[IntNodeValue] { return newTestGraph(2, [2]int{0, 2}) },
+			expected: []error{ErrDanglingEdge},
+		},
+		{
+			name:     "negative index",
+			graph:    func() *Graph[IntNodeValue] { return newTestGraph(2, [2]int{-1, 0}) },
+			expected: []error{ErrDanglingEdge},
+		},
+		{
+			name:     "self-loop",
+			graph:    func() *Graph[IntNodeValue] { return newTestGraph(2, [2]int{1, 1}) },
+			expected: []error{ErrSelfLoop},
+		},
+		{
+			name:     "reversed duplicate",
+			graph:    func() *Graph[IntNodeValue] { return newTestGraph(2, [2]int{0, 1}, [2]int{1, 0}) },
+			expected: []error{ErrDuplicateEdge},
+		},
+		{
+			name: "node id mismatch",
+			graph: func() *Graph[IntNodeValue] {
+				g := newTestGraph(2)
+				g.nodes[1].Id = 5
+				return g
+			},
+			expected: []error{ErrNodeIdMismatch},
+		},
+		{
+			name: "every problem",
+			graph: func() *Graph[IntNodeValue] {
+				return newTestGraph(2, [2]int{0, 1}, [2]int{0, 1}, [2]int{0, 0}, [2]int{1, 3})
+			},
+			expected: []error{ErrDuplicateEdge, ErrSelfLoop, ErrDanglingEdge},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.graph().Validate()
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			for _, expected := range tt.expected {
+				if !errors.Is(err, expected) {
+					t.Errorf("Validate() = %v, want %v", err, expected)
+				}
+			}
+			if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != len(tt.expected) {
+				t.Errorf("Validate() = %v, want %d problems", err, len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	g := newTestGraph(4, [2]int{1, 0}, [2]int{0, 1}, [2]int{2, 1}, [2]int{3, 3}, [2]int{1, 2}, [2]int{3, 0})
+
+	if removed := g.Normalize(); removed != 2 {
+		t.Errorf("Normalize() removed %d edges, want 2", removed)
+	}
+
+	expected := []Edge{{From: 0, To: 1}, {From: 1, To: 2}, {From: 3, To: 3}, {From: 0, To: 3}}
+	if edges := g.GetEdges(); !slices.Equal(edges, expected) {
+		t.Errorf("edges = %v, want %v", edges, expected)
+	}
+	if neighbors := slices.Collect(g.Neighbors(1)); !slices.Equal(neighbors, []int{0, 2}) {
+		t.Errorf("Neighbors(1) = %v, want [0 2]", neighbors)
+	}
+
+	if err := g.Validate(); !errors.Is(err, ErrSelfLoop) || errors.Is(err, ErrDuplicateEdge) {
+		t.Errorf("Validate() after Normalize() = %v, want only the self-loop", err)
+	}
+
+	if removed := g.Normalize(); removed != 0 {
+		t.Errorf("second Normalize() removed %d edges, want 0", removed)
+	}
+}
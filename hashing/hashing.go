@@ -0,0 +1,155 @@
+// Package hashing is a registry of the hash algorithms that can be used for
+// commitments. Every algorithm has a one byte identifier that is stored in
+// proofs, so a verifier knows which algorithm the prover used.
+package hashing
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+type Algorithm uint8
+
+const (
+	SHA1 Algorithm = iota + 1
+	SHA256
+	SHA512_256
+	SHA3_256
+	BLAKE2b_256
+)
+
+// Default is the algorithm used when none is chosen.
+const Default = SHA256
+
+var ErrUnknownAlgorithm = errors.New("unknown hash algorithm")
+
+type entry struct {
+	name    string
+	newHash func() hash.Hash
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[Algorithm]entry)
+)
+
+func init() {
+	Register(SHA1, "sha1", sha1.New)
+	Register(SHA256, "sha256", sha256.New)
+	Register(SHA512_256, "sha512/256", sha512.New512_256)
+	Register(SHA3_256, "sha3-256", func() hash.Hash { return sha3.New256() })
+	Register(BLAKE2b_256, "blake2b-256", func() hash.Hash {
+		// New256 only fails for keys longer than 64 bytes
+		h, _ := blake2b.New256(nil)
+		return h
+	})
+}
+
+// Register makes a hash algorithm available under the given identifier.
+// It panics if the identifier is zero or is already registered.
+func Register(alg Algorithm, name string, newHash func() hash.Hash) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if alg == 0 {
+		panic("hashing: algorithm identifier 0 is reserved")
+	}
+	if _, ok := registry[alg]; ok {
+		panic(fmt.Sprintf("hashing: algorithm %d registered twice", alg))
+	}
+	registry[alg] = entry{name: name, newHash: newHash}
+}
+
+// Parse returns the algorithm registered under the given name.
+func Parse(name string) (Algorithm, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for alg, e := range registry {
+		if e.name == name {
+			return alg, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+}
+
+func lookup(alg Algorithm) (entry, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	e, ok := registry[alg]
+	return e, ok
+}
+
+// Available reports whether the algorithm is registered.
+func (a Algorithm) Available() bool {
+	_, ok := lookup(a)
+	return ok
+}
+
+func (a Algorithm) String() string {
+	if e, ok := lookup(a); ok {
+		return e.name
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(a))
+}
+
+// New returns a new hash.Hash computing the algorithm.
+// It panics if the algorithm is not registered.
+func (a Algorithm) New() hash.Hash {
+	e, ok := lookup(a)
+	if !ok {
+		panic(fmt.Sprintf("hashing: %v", a))
+	}
+	return e.newHash()
+}
+
+// Size returns the length in bytes of the algorithm's digest.
+// It panics if the algorithm is not registered.
+func (a Algorithm) Size() int {
+	return a.New().Size()
+}
+
+// Sum returns the digest of data.
+// It panics if the algorithm is not registered.
+func (a Algorithm) Sum(data []byte) []byte {
+	h := a.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// Policy is the set of algorithms a verifier accepts.
+type Policy struct {
+	allowed map[Algorithm]struct{}
+}
+
+// NewPolicy creates a policy accepting only the given algorithms.
+func NewPolicy(algs ...Algorithm) Policy {
+	allowed := make(map[Algorithm]struct{}, len(algs))
+	for _, alg := range algs {
+		allowed[alg] = struct{}{}
+	}
+	return Policy{allowed: allowed}
+}
+
+// DefaultPolicy accepts every built-in algorithm except SHA-1.
+func DefaultPolicy() Policy {
+	return NewPolicy(SHA256, SHA512_256, SHA3_256, BLAKE2b_256)
+}
+
+// Allows reports whether the algorithm is accepted by the policy and
+// registered.
+func (p Policy) Allows(alg Algorithm) bool {
+	if _, ok := p.allowed[alg]; !ok {
+		return false
+	}
+	return alg.Available()
+}
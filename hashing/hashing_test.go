@@ -0,0 +1,116 @@
+package hashing
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestAlgorithmSum(t *testing.T) {
+	tests := []struct {
+		name     string
+		alg      Algorithm
+		expected string
+	}{
+		{
+			name:     "sha1",
+			alg:      SHA1,
+			expected: "a9993e364706816aba3e25717850c26c9cd0d89d",
+		},
+		{
+			name:     "sha256",
+			alg:      SHA256,
+			expected: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			name:     "sha512/256",
+			alg:      SHA512_256,
+			expected: "53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23",
+		},
+		{
+			name:     "sha3-256",
+			alg:      SHA3_256,
+			expected: "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+		},
+		{
+			name:     "blake2b-256",
+			alg:      BLAKE2b_256,
+			expected: "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := hex.EncodeToString(tt.alg.Sum([]byte("abc")))
+			if sum != tt.expected {
+				t.Errorf("Sum(abc) = %s, want %s", sum, tt.expected)
+			}
+			if tt.alg.Size() != len(tt.expected)/2 {
+				t.Errorf("Size() = %d, want %d", tt.alg.Size(), len(tt.expected)/2)
+			}
+			if tt.alg.String() != tt.name {
+				t.Errorf("String() = %s, want %s", tt.alg.String(), tt.name)
+			}
+
+			parsed, err := Parse(tt.name)
+			if err != nil {
+				t.Fatalf("Parse(%s) failed: %v", tt.name, err)
+			}
+			if parsed != tt.alg {
+				t.Errorf("Parse(%s) = %v, want %v", tt.name, parsed, tt.alg)
+			}
+		})
+	}
+}
+
+func TestUnknownAlgorithm(t *testing.T) {
+	unknown := Algorithm(200)
+	if unknown.Available() {
+		t.Error("unregistered algorithm should not be available")
+	}
+	if unknown.String() != "unknown(200)" {
+		t.Errorf("String() = %s", unknown.String())
+	}
+	if _, err := Parse("md5"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Parse(md5) error = %v, want ErrUnknownAlgorithm", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("New() on an unknown algorithm should panic")
+		}
+	}()
+	unknown.New()
+}
+
+func TestPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+	if policy.Allows(SHA1) {
+		t.Error("default policy should not allow sha1")
+	}
+	for _, alg := range []Algorithm{SHA256, SHA512_256, SHA3_256, BLAKE2b_256} {
+		if !policy.Allows(alg) {
+			t.Errorf("default policy should allow %v", alg)
+		}
+	}
+
+	policy = NewPolicy(SHA3_256, Algorithm(200))
+	if !policy.Allows(SHA3_256) {
+		t.Error("policy should allow sha3-256")
+	}
+	if policy.Allows(SHA256) {
+		t.Error("policy should not allow sha256")
+	}
+	if policy.Allows(Algorithm(200)) {
+		t.Error("policy should not allow an unregistered algorithm")
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering an algorithm twice should panic")
+		}
+	}()
+	Register(SHA256, "sha256", nil)
+}
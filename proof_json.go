@@ -0,0 +1,117 @@
+package zkp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/hvuhsg/zkp/hashing"
+)
+
+// proofJSON is the JSON representation of a proof.
+type proofJSON struct {
+	Version       int              `json:"version"`
+	HashAlgorithm uint8            `json:"hash_algorithm"`
+	Statement     []byte           `json:"statement"`
+	Rounds        []proofRoundJSON `json:"rounds"`
+}
+
+type proofRoundJSON struct {
+	Commitment []byte   `json:"commitment"`
+	Edge       uint64   `json:"edge"`
+	Opening    []string `json:"opening"`
+}
+
+// MarshalJSON encodes the proof as a JSON object
+// the schema is as follows:
+//
+//	{
+//	  "version": 1,                  // format version, always 1
+//	  "hash_algorithm": 2,           // hashing.Algorithm identifier
+//	  "statement": "<base64>",       // fingerprint of the public graph
+//	  "rounds": [
+//	    {
+//	      "commitment": "<base64>",  // serialized commitment graph
+//	      "edge": 0,                 // index of the opened edge
+//	      "opening": ["<color>|<salt>", "<color>|<salt>"]
+//	    }
+//	  ]
+//	}
+//
+// The JSON and binary encodings carry the same data, so a proof can be
+// converted between them without being verified again. JSON strings can only
+// hold valid UTF-8, so a proof whose opened values are not valid UTF-8, such
+// as a proof for a graph with such color names, is rejected with
+// ErrInvalidProofFormat and can only be encoded in the binary format.
+func (p *Proof) MarshalJSON() ([]byte, error) {
+	if err := p.checkEncodable(); err != nil {
+		return nil, err
+	}
+
+	rounds := make([]proofRoundJSON, len(p.commitementGraphs))
+	for i, commitment := range p.commitementGraphs {
+		if !utf8.ValidString(p.edgeValues[i][0]) || !utf8.ValidString(p.edgeValues[i][1]) {
+			return nil, fmt.Errorf("%w: opened value of round %d is not valid UTF-8", ErrInvalidProofFormat, i)
+		}
+		rounds[i] = proofRoundJSON{
+			Commitment: commitment,
+			Edge:       p.edgeIds[i],
+			Opening:    []string{p.edgeValues[i][0], p.edgeValues[i][1]},
+		}
+	}
+
+	return json.Marshal(proofJSON{
+		Version:       proofVersion,
+		HashAlgorithm: uint8(p.hashAlgorithm),
+		Statement:     p.statement,
+		Rounds:        rounds,
+	})
+}
+
+// UnmarshalJSON decodes a proof encoded by MarshalJSON.
+// Unknown fields are rejected.
+func (p *Proof) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var encoded proofJSON
+	if err := decoder.Decode(&encoded); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProofFormat, err)
+	}
+
+	if encoded.Version != proofVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidProofFormat, encoded.Version)
+	}
+
+	proof := Proof{
+		hashAlgorithm:     hashing.Algorithm(encoded.HashAlgorithm),
+		statement:         encoded.Statement,
+		commitementGraphs: make([]CommitementGraphPayload, len(encoded.Rounds)),
+		edgeIds:           make([]uint64, len(encoded.Rounds)),
+		edgeValues:        make([][2]string, len(encoded.Rounds)),
+	}
+	if proof.statement == nil {
+		proof.statement = []byte{}
+	}
+
+	for i, round := range encoded.Rounds {
+		if len(round.Opening) != 2 {
+			return fmt.Errorf("%w: round %d opens %d values instead of 2", ErrInvalidProofFormat, i, len(round.Opening))
+		}
+		proof.commitementGraphs[i] = round.Commitment
+		if proof.commitementGraphs[i] == nil {
+			proof.commitementGraphs[i] = CommitementGraphPayload{}
+		}
+		proof.edgeIds[i] = round.Edge
+		proof.edgeValues[i] = [2]string{round.Opening[0], round.Opening[1]}
+	}
+
+	// Only accept proofs that can also be encoded in the binary format
+	if err := proof.checkEncodable(); err != nil {
+		return err
+	}
+
+	*p = proof
+	return nil
+}
@@ -0,0 +1,133 @@
+package zkp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ json.Marshaler   = (*Proof)(nil)
+	_ json.Unmarshaler = (*Proof)(nil)
+)
+
+func TestProofJSONRoundTrip(t *testing.T) {
+	proof := createTriangleProof(t, 5)
+
+	data, err := json.Marshal(proof)
+	require.NoError(t, err)
+
+	var decoded Proof
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, proof, &decoded)
+	assert.True(t, decoded.Verify(), "Decoded proof should verify")
+}
+
+func TestProofJSONMatchesBinary(t *testing.T) {
+	proof := createTriangleProof(t, 5)
+	binaryData, err := proof.MarshalBinary()
+	require.NoError(t, err)
+
+	// binary -> JSON -> binary
+	fromBinary, err := DecodeProof(binaryData)
+	require.NoError(t, err)
+	jsonData, err := json.Marshal(fromBinary)
+	require.NoError(t, err)
+
+	var fromJSON Proof
+	require.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+	again, err := fromJSON.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, binaryData, again)
+}
+
+func TestProofJSONRejectsInvalidUTF8(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("\xff"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+	proof := NewProofer(graph).CreateProof(3)
+
+	_, err := json.Marshal(proof)
+	assert.ErrorIs(t, err, ErrInvalidProofFormat)
+
+	// The binary form keeps the bytes as they are
+	data, err := proof.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err := DecodeProof(data)
+	require.NoError(t, err)
+	assert.True(t, decoded.Verify())
+}
+
+func TestProofJSONSchema(t *testing.T) {
+	proof := createTriangleProof(t, 2)
+
+	data, err := json.Marshal(proof)
+	require.NoError(t, err)
+
+	var schema struct {
+		Version       int    `json:"version"`
+		HashAlgorithm int    `json:"hash_algorithm"`
+		Statement     string `json:"statement"`
+		Rounds        []struct {
+			Commitment string   `json:"commitment"`
+			Edge       uint64   `json:"edge"`
+			Opening    []string `json:"opening"`
+		} `json:"rounds"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	assert.Equal(t, 1, schema.Version)
+	assert.Equal(t, int(proof.hashAlgorithm), schema.HashAlgorithm)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(proof.statement), schema.Statement)
+	require.Len(t, schema.Rounds, 2)
+	for i, round := range schema.Rounds {
+		assert.Equal(t, base64.StdEncoding.EncodeToString(proof.commitementGraphs[i]), round.Commitment)
+		assert.Equal(t, proof.edgeIds[i], round.Edge)
+		assert.Equal(t, proof.edgeValues[i][:], round.Opening)
+	}
+}
+
+func TestProofJSONRejectsInvalidData(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "not an object",
+			data: `[]`,
+		},
+		{
+			name: "unsupported version",
+			data: `{"version": 2, "hash_algorithm": 2, "statement": "", "rounds": []}`,
+		},
+		{
+			name: "unknown field",
+			data: `{"version": 1, "hash_algorithm": 2, "statement": "", "rounds": [], "extra": true}`,
+		},
+		{
+			name: "single opened value",
+			data: `{"version": 1, "hash_algorithm": 2, "statement": "", "rounds": [{"commitment": "", "edge": 0, "opening": ["red|a"]}]}`,
+		},
+		{
+			name: "three opened values",
+			data: `{"version": 1, "hash_algorithm": 2, "statement": "", "rounds": [{"commitment": "", "edge": 0, "opening": ["red|a", "blue|b", "green|c"]}]}`,
+		},
+		{
+			name: "invalid base64",
+			data: `{"version": 1, "hash_algorithm": 2, "statement": "!!!", "rounds": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var proof Proof
+			err := json.Unmarshal([]byte(tt.data), &proof)
+			assert.ErrorIs(t, err, ErrInvalidProofFormat)
+		})
+	}
+}
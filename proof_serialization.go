@@ -0,0 +1,232 @@
+package zkp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/hvuhsg/zkp/hashing"
+)
+
+const (
+	proofVersion = 0b00000001
+
+	// minRoundSize is the size of a round with an empty commitment and empty
+	// opened values (4+8+2+2).
+	minRoundSize = 16
+)
+
+var (
+	ErrTruncatedProof     = errors.New("proof data is truncated")
+	ErrTrailingProofData  = errors.New("proof data has trailing bytes")
+	ErrInvalidProofFormat = errors.New("invalid proof format")
+)
+
+// MarshalBinary encodes the proof into a byte array
+// the format is as follows:
+// [version][hash_algorithm][statement_size][statement][rounds_count][round1][round2]...
+// and every round is encoded as:
+// [commitment_size][commitment][edge_id][value1_size][value1][value2_size][value2]
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	if err := p.checkEncodable(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	// Write version and hash algorithm (1 byte each)
+	buf.WriteByte(proofVersion)
+	buf.WriteByte(byte(p.hashAlgorithm))
+
+	// Write statement (2 bytes size)
+	buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(p.statement))))
+	buf.Write(p.statement)
+
+	// Write rounds count (4 bytes)
+	buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(p.commitementGraphs))))
+
+	for i, commitment := range p.commitementGraphs {
+		// Write commitment (4 bytes size since it could be large)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(commitment))))
+		buf.Write(commitment)
+
+		// Write edge id (8 bytes)
+		buf.Write(binary.BigEndian.AppendUint64(nil, p.edgeIds[i]))
+
+		// Write opened values (2 bytes size each)
+		for _, value := range p.edgeValues[i] {
+			buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(value))))
+			buf.WriteString(value)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// checkEncodable checks that the proof fits in the binary format.
+func (p *Proof) checkEncodable() error {
+	if len(p.edgeIds) != len(p.commitementGraphs) || len(p.edgeValues) != len(p.commitementGraphs) {
+		return fmt.Errorf("%w: rounds have different lengths", ErrInvalidProofFormat)
+	}
+	if len(p.statement) > math.MaxUint16 {
+		return fmt.Errorf("%w: statement too large", ErrInvalidProofFormat)
+	}
+	if uint64(len(p.commitementGraphs)) > math.MaxUint32 {
+		return fmt.Errorf("%w: too many rounds", ErrInvalidProofFormat)
+	}
+
+	for i, commitment := range p.commitementGraphs {
+		if uint64(len(commitment)) > math.MaxUint32 {
+			return fmt.Errorf("%w: commitment %d too large", ErrInvalidProofFormat, i)
+		}
+		for _, value := range p.edgeValues[i] {
+			if len(value) > math.MaxUint16 {
+				return fmt.Errorf("%w: opened value of round %d too large", ErrInvalidProofFormat, i)
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalBinary decodes a proof encoded by MarshalBinary.
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	proof, err := DecodeProof(data)
+	if err != nil {
+		return err
+	}
+	*p = *proof
+	return nil
+}
+
+// DecodeProof decodes a proof encoded by MarshalBinary.
+// It fails if data is truncated or has bytes after the last round.
+func DecodeProof(data []byte) (*Proof, error) {
+	d := &proofDecoder{data: data}
+
+	version, err := d.uint8()
+	if err != nil {
+		return nil, err
+	}
+	if version != proofVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidProofFormat, version)
+	}
+
+	hashAlgorithm, err := d.uint8()
+	if err != nil {
+		return nil, err
+	}
+
+	statementSize, err := d.uint16()
+	if err != nil {
+		return nil, err
+	}
+	statement, err := d.bytes(int(statementSize))
+	if err != nil {
+		return nil, err
+	}
+
+	roundsCount, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+	// Do not trust the count for the allocation before checking it can fit
+	if uint64(roundsCount)*minRoundSize > uint64(d.remaining()) {
+		return nil, fmt.Errorf("%w: %d rounds do not fit in %d bytes", ErrTruncatedProof, roundsCount, d.remaining())
+	}
+
+	proof := &Proof{
+		hashAlgorithm:     hashing.Algorithm(hashAlgorithm),
+		statement:         bytes.Clone(statement),
+		commitementGraphs: make([]CommitementGraphPayload, roundsCount),
+		edgeIds:           make([]uint64, roundsCount),
+		edgeValues:        make([][2]string, roundsCount),
+	}
+
+	for i := range proof.commitementGraphs {
+		commitmentSize, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		commitment, err := d.bytes(int(commitmentSize))
+		if err != nil {
+			return nil, err
+		}
+		proof.commitementGraphs[i] = bytes.Clone(commitment)
+
+		if proof.edgeIds[i], err = d.uint64(); err != nil {
+			return nil, err
+		}
+
+		for j := range proof.edgeValues[i] {
+			valueSize, err := d.uint16()
+			if err != nil {
+				return nil, err
+			}
+			value, err := d.bytes(int(valueSize))
+			if err != nil {
+				return nil, err
+			}
+			proof.edgeValues[i][j] = string(value)
+		}
+	}
+
+	if d.remaining() != 0 {
+		return nil, fmt.Errorf("%w: %d bytes after the last round", ErrTrailingProofData, d.remaining())
+	}
+
+	return proof, nil
+}
+
+// proofDecoder reads big endian values from a byte array and fails with
+// ErrTruncatedProof instead of reading past its end.
+type proofDecoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *proofDecoder) remaining() int {
+	return len(d.data) - d.offset
+}
+
+func (d *proofDecoder) bytes(n int) ([]byte, error) {
+	if n < 0 || n > d.remaining() {
+		return nil, fmt.Errorf("%w: need %d bytes at offset %d, have %d", ErrTruncatedProof, n, d.offset, d.remaining())
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *proofDecoder) uint8() (uint8, error) {
+	b, err := d.bytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *proofDecoder) uint16() (uint16, error) {
+	b, err := d.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+func (d *proofDecoder) uint32() (uint32, error) {
+	b, err := d.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+func (d *proofDecoder) uint64() (uint64, error) {
+	b, err := d.bytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
@@ -0,0 +1,110 @@
+package zkp
+
+import (
+	"encoding"
+	"testing"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/hashing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Proof)(nil)
+	_ encoding.BinaryUnmarshaler = (*Proof)(nil)
+)
+
+func createTriangleProof(t *testing.T, length int) *Proof {
+	t.Helper()
+
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(0, 2)
+
+	return NewProofer(graph, WithHashAlgorithm(hashing.SHA3_256)).CreateProof(length)
+}
+
+func TestProofBinaryRoundTrip(t *testing.T) {
+	proof := createTriangleProof(t, 5)
+
+	data, err := proof.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded Proof
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, proof, &decoded)
+	assert.Equal(t, hashing.SHA3_256, decoded.HashAlgorithm())
+	assert.True(t, decoded.Verify(), "Decoded proof should verify")
+
+	// Encoding the decoded proof gives the same bytes
+	again, err := decoded.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestProofBinaryEmpty(t *testing.T) {
+	proof := &Proof{hashAlgorithm: hashing.SHA256}
+
+	data, err := proof.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		proofVersion,         // version
+		byte(hashing.SHA256), // hash algorithm
+		0, 0,                 // statement size (0)
+		0, 0, 0, 0, // rounds count (0)
+	}, data)
+
+	decoded, err := DecodeProof(data)
+	require.NoError(t, err)
+	assert.Empty(t, decoded.commitementGraphs)
+}
+
+func TestDecodeProofRejectsTruncatedData(t *testing.T) {
+	data, err := createTriangleProof(t, 3).MarshalBinary()
+	require.NoError(t, err)
+
+	for size := range len(data) {
+		_, err := DecodeProof(data[:size])
+		assert.ErrorIs(t, err, ErrTruncatedProof, "decoding %d of %d bytes", size, len(data))
+	}
+}
+
+func TestDecodeProofRejectsTrailingData(t *testing.T) {
+	data, err := createTriangleProof(t, 3).MarshalBinary()
+	require.NoError(t, err)
+
+	_, err = DecodeProof(append(data, 0))
+	assert.ErrorIs(t, err, ErrTrailingProofData)
+
+	var proof Proof
+	assert.ErrorIs(t, proof.UnmarshalBinary(append(data, 1, 2, 3)), ErrTrailingProofData)
+}
+
+func TestDecodeProofRejectsInvalidData(t *testing.T) {
+	data, err := createTriangleProof(t, 3).MarshalBinary()
+	require.NoError(t, err)
+
+	// Unknown version
+	badVersion := append([]byte{}, data...)
+	badVersion[0] = 0xFF
+	_, err = DecodeProof(badVersion)
+	assert.ErrorIs(t, err, ErrInvalidProofFormat)
+
+	// A rounds count that cannot fit in the data is rejected before allocating
+	statementSize := int(data[2])<<8 | int(data[3])
+	hugeCount := append([]byte{}, data...)
+	copy(hugeCount[4+statementSize:], []byte{0xFF, 0xFF, 0xFF, 0xFF})
+	_, err = DecodeProof(hugeCount)
+	assert.ErrorIs(t, err, ErrTruncatedProof)
+
+	// Inconsistent proofs cannot be encoded
+	proof := createTriangleProof(t, 3)
+	proof.edgeIds = proof.edgeIds[:2]
+	_, err = proof.MarshalBinary()
+	assert.ErrorIs(t, err, ErrInvalidProofFormat)
+}
@@ -4,7 +4,10 @@ import (
 	"testing"
 
 	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/graph"
+	"github.com/hvuhsg/zkp/hashing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVerifyValidProof(t *testing.T) {
@@ -53,41 +56,29 @@ func TestVerifyInvalidProof(t *testing.T) {
 	graph.AddEdge(1, 2)
 	graph.AddEdge(0, 2)
 
-	// Create a proof
-	proofer := NewProofer(graph)
+	// Create a proof, which the proofer only allows with the checks turned off
+	proofer := NewProofer(graph, WithoutInputChecks())
 	proof := proofer.CreateProof(100)
 
 	// Test that a valid proof verifies
 	assert.False(t, proof.Verify(), "Invalid proof should fail verification")
 }
 
-func TestIsEdgeValuesValid(t *testing.T) {
+func TestCheckEdgeValues(t *testing.T) {
 	// Test valid edge values with different colors
-	assert.True(t, isEdgeValuesValid([2]string{"red|abc123", "blue|def456"}))
-	assert.True(t, isEdgeValuesValid([2]string{"blue|abc123", "green|def456"}))
-	assert.True(t, isEdgeValuesValid([2]string{"green|abc123", "red|def456"}))
+	assert.NoError(t, checkEdgeValues([2]string{"red|abc123", "blue|def456"}))
+	assert.NoError(t, checkEdgeValues([2]string{"blue|abc123", "green|def456"}))
+	assert.NoError(t, checkEdgeValues([2]string{"green|abc123", "red|def456"}))
 
 	// Test invalid edge values with same color
-	assert.False(t, isEdgeValuesValid([2]string{"red|abc123", "red|def456"}))
-	assert.False(t, isEdgeValuesValid([2]string{"blue|abc123", "blue|def456"}))
-	assert.False(t, isEdgeValuesValid([2]string{"green|abc123", "green|def456"}))
+	assert.ErrorIs(t, checkEdgeValues([2]string{"red|abc123", "red|def456"}), ErrSameColor)
+	assert.ErrorIs(t, checkEdgeValues([2]string{"blue|abc123", "blue|def456"}), ErrSameColor)
+	assert.ErrorIs(t, checkEdgeValues([2]string{"green|abc123", "green|def456"}), ErrSameColor)
 
 	// Test invalid edge values with wrong format
-	assert.False(t, isEdgeValuesValid([2]string{"red", "blue"}))
-	assert.False(t, isEdgeValuesValid([2]string{"red|abc123", "blue"}))
-	assert.False(t, isEdgeValuesValid([2]string{"", ""}))
-}
-
-func TestIsExpectedEdgeIdValid(t *testing.T) {
-	// Test valid edge IDs
-	assert.True(t, isExpectedEdgeIdValid(5, 3, 3))
-	assert.True(t, isExpectedEdgeIdValid(5, 8, 3))
-	assert.True(t, isExpectedEdgeIdValid(5, 13, 3))
-
-	// Test invalid edge IDs
-	assert.False(t, isExpectedEdgeIdValid(5, 3, 4))
-	assert.False(t, isExpectedEdgeIdValid(5, 8, 2))
-	assert.False(t, isExpectedEdgeIdValid(5, 13, 1))
+	assert.ErrorIs(t, checkEdgeValues([2]string{"red", "blue"}), ErrOpeningMismatch)
+	assert.ErrorIs(t, checkEdgeValues([2]string{"red|abc123", "blue"}), ErrOpeningMismatch)
+	assert.ErrorIs(t, checkEdgeValues([2]string{"", ""}), ErrOpeningMismatch)
 }
 
 func TestGetColorFromNodeValue(t *testing.T) {
@@ -113,3 +104,377 @@ func TestGetColorFromNodeValue(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid node value")
 }
+
+func publicGraph(cg *coloringgraph.ColoringGraph) *coloringgraph.ColoringGraph {
+	public := coloringgraph.NewColoringGraph()
+	for range cg.GetNodes() {
+		public.AddNode(coloringgraph.ColorNodeValue(""))
+	}
+	for _, edge := range cg.GetEdges() {
+		public.AddEdge(edge.From, edge.To)
+	}
+	return public
+}
+
+func TestVerifyStatement(t *testing.T) {
+	// Create a square with a diagonal
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(2, 3)
+	graph.AddEdge(3, 0)
+	graph.AddEdge(0, 2)
+
+	proof := NewProofer(graph).CreateProof(10)
+
+	assert.True(t, proof.VerifyStatement(publicGraph(graph)), "Proof should verify against its public graph")
+	assert.False(t, proof.VerifyStatement(nil), "Proof should not verify against a nil graph")
+
+	// A different edge set is a different statement
+	other := publicGraph(graph)
+	other.AddNode(coloringgraph.ColorNodeValue(""))
+	other.AddEdge(3, 4)
+	assert.False(t, proof.VerifyStatement(other), "Proof should not verify against another graph")
+
+	// Tampering with the statement breaks the proof
+	proof.statement[0] ^= 0xFF
+	assert.False(t, proof.Verify(), "Proof with a modified statement should fail verification")
+	proof.statement[0] ^= 0xFF
+}
+
+func TestVerifyStatementRejectsSubstitutedGraph(t *testing.T) {
+	// The public graph
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(2, 3)
+	graph.AddEdge(3, 0)
+
+	// A cheating prover commits to an easy triangle instead
+	triangle := coloringgraph.NewColoringGraph()
+	triangle.AddNode(coloringgraph.ColorNodeValue("red"))
+	triangle.AddNode(coloringgraph.ColorNodeValue("blue"))
+	triangle.AddNode(coloringgraph.ColorNodeValue("green"))
+	triangle.AddEdge(0, 1)
+	triangle.AddEdge(1, 2)
+	triangle.AddEdge(0, 2)
+
+	proof := NewProofer(triangle).CreateProof(10)
+
+	assert.True(t, proof.Verify(), "Proof is internally consistent")
+	assert.False(t, proof.VerifyStatement(publicGraph(graph)), "Proof for another graph should fail verification")
+
+	// Pretending the proof is about the public graph breaks the challenges
+	proof.statement = statementFingerprint(graph.Graph)
+	assert.False(t, proof.VerifyStatement(publicGraph(graph)), "Proof with a substituted statement should fail verification")
+}
+
+func TestVerifyStatementRejectsDuplicatedEdges(t *testing.T) {
+	// A triangle has no 2-coloring
+	triangle := coloringgraph.NewColoringGraph()
+	triangle.AddNode(coloringgraph.ColorNodeValue("red"))
+	triangle.AddNode(coloringgraph.ColorNodeValue("red"))
+	triangle.AddNode(coloringgraph.ColorNodeValue("blue"))
+	triangle.AddEdge(0, 1)
+	triangle.AddEdge(1, 2)
+	triangle.AddEdge(0, 2)
+
+	// Copies of a properly colored edge keep the fingerprint and make the
+	// challenges almost never open the badly colored edge
+	padded := triangle.Clone()
+	for range 2000 {
+		padded.AddEdge(1, 2)
+	}
+	proof, err := NewProofer(padded, WithoutInputChecks()).Prove(20)
+	require.NoError(t, err)
+
+	// The public graph limits the number of committed edges
+	_, err = proof.VerifyStatementDetailed(publicGraph(triangle))
+	assert.ErrorIs(t, err, ErrMalformedCommitment)
+	assert.ErrorIs(t, err, graph.ErrLimitExceeded)
+
+	// A public graph with repeated edges does not allow them either
+	_, err = proof.VerifyStatementDetailed(publicGraph(padded))
+	assert.ErrorIs(t, err, ErrMalformedCommitment)
+
+	// Without a public graph the repeated edges themselves are rejected
+	_, err = proof.VerifyDetailed()
+	assert.ErrorIs(t, err, ErrStatementMismatch)
+	assert.ErrorIs(t, err, graph.ErrDuplicateEdge)
+}
+
+func TestVerifyHashPolicy(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(0, 2)
+
+	for _, alg := range []hashing.Algorithm{hashing.SHA256, hashing.SHA512_256, hashing.SHA3_256, hashing.BLAKE2b_256} {
+		t.Run(alg.String(), func(t *testing.T) {
+			proof := NewProofer(graph, WithHashAlgorithm(alg)).CreateProof(5)
+			assert.Equal(t, alg, proof.HashAlgorithm())
+			assert.True(t, proof.Verify(), "Proof should verify with the default policy")
+			assert.True(t, proof.VerifyStatement(publicGraph(graph)), "Proof should verify against its public graph")
+
+			// A policy that does not include the algorithm rejects the proof
+			verifier := NewVerifier(WithHashPolicy(hashing.NewPolicy()))
+			assert.False(t, verifier.Verify(proof), "Proof should fail with an empty policy")
+			assert.False(t, verifier.VerifyStatement(proof, publicGraph(graph)), "Proof should fail with an empty policy")
+		})
+	}
+
+	// SHA-1 is only accepted when the policy allows it explicitly
+	proof := NewProofer(graph, WithHashAlgorithm(hashing.SHA1)).CreateProof(5)
+	assert.False(t, proof.Verify(), "SHA-1 proof should fail with the default policy")
+	assert.True(t, NewVerifier(WithHashPolicy(hashing.NewPolicy(hashing.SHA1))).Verify(proof), "SHA-1 proof should verify when allowed")
+
+	// Claiming another algorithm than the one used breaks the proof
+	proof = NewProofer(graph).CreateProof(5)
+	proof.hashAlgorithm = hashing.SHA3_256
+	assert.False(t, proof.Verify(), "Proof with a substituted hash algorithm should fail verification")
+}
+
+func TestVerifyConcurrency(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	colors := []string{"red", "blue", "green"}
+	for i := range 12 {
+		graph.AddNode(coloringgraph.ColorNodeValue(colors[i%3]))
+		if i > 0 {
+			graph.AddEdge(i-1, i)
+		}
+	}
+
+	proof := NewProofer(graph).CreateProof(40)
+
+	for _, concurrency := range []int{1, 3, 16, 100} {
+		verifier := NewVerifier(WithVerifierConcurrency(concurrency))
+		assert.True(t, verifier.Verify(proof), "concurrency %d", concurrency)
+		assert.True(t, verifier.VerifyStatement(proof, publicGraph(graph)), "concurrency %d", concurrency)
+
+		// A single bad round anywhere in the proof is caught
+		for _, round := range []int{0, 17, 39} {
+			original := proof.edgeValues[round]
+			proof.edgeValues[round] = [2]string{original[0], original[0]}
+			assert.False(t, verifier.Verify(proof), "concurrency %d, bad round %d", concurrency, round)
+			proof.edgeValues[round] = original
+
+			originalCommitment := proof.commitementGraphs[round]
+			proof.commitementGraphs[round] = []byte("invalid")
+			assert.False(t, verifier.VerifyStatement(proof, publicGraph(graph)), "concurrency %d, bad commitment %d", concurrency, round)
+			proof.commitementGraphs[round] = originalCommitment
+		}
+	}
+}
+
+func TestVerifyDetailedReport(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(0, 2)
+
+	proof := NewProofer(graph).CreateProof(10)
+
+	report, err := proof.VerifyStatementDetailed(publicGraph(graph))
+	require.NoError(t, err)
+	assert.Equal(t, hashing.Default, report.HashAlgorithm)
+	assert.Equal(t, 3, report.Nodes)
+	assert.Equal(t, 3, report.Edges)
+	assert.Equal(t, SoundnessBits(3, 10), report.SoundnessBits)
+	require.Len(t, report.Rounds, 10)
+	for i, round := range report.Rounds {
+		assert.Equal(t, proof.edgeIds[i], round.Edge)
+		edge := graph.GetEdges()[round.Edge]
+		assert.Equal(t, edge.From, round.From)
+		assert.Equal(t, edge.To, round.To)
+	}
+
+}
+
+func TestVerifyRejectsProofWithoutRounds(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddEdge(0, 1)
+
+	// Nothing but the statement is needed to build a proof without rounds
+	proof := &Proof{hashAlgorithm: hashing.Default, statement: statementFingerprint(graph.Graph)}
+
+	_, err := proof.VerifyDetailed()
+	assert.ErrorIs(t, err, ErrMalformedProof)
+	_, err = proof.VerifyStatementDetailed(publicGraph(graph))
+	assert.ErrorIs(t, err, ErrMalformedProof)
+
+	data, err := proof.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err := DecodeProof(data)
+	require.NoError(t, err)
+	assert.False(t, decoded.VerifyStatement(publicGraph(graph)))
+}
+
+func TestVerifyDetailedErrors(t *testing.T) {
+	// With a single edge every challenge opens edge 0, so tampering with a
+	// round does not move the challenges of the other rounds
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+
+	invalid := coloringgraph.NewColoringGraph()
+	invalid.AddNode(coloringgraph.ColorNodeValue("red"))
+	invalid.AddNode(coloringgraph.ColorNodeValue("red"))
+	invalid.AddEdge(0, 1)
+
+	tests := []struct {
+		name     string
+		proof    func() *Proof
+		expected error
+		round    int
+	}{
+		{
+			name: "same color",
+			proof: func() *Proof {
+				return NewProofer(invalid, WithoutInputChecks()).CreateProof(5)
+			},
+			expected: ErrSameColor,
+			round:    0,
+		},
+		{
+			name: "challenge mismatch",
+			proof: func() *Proof {
+				proof := NewProofer(graph).CreateProof(5)
+				proof.edgeIds[2] = 1
+				return proof
+			},
+			expected: ErrChallengeMismatch,
+			round:    2,
+		},
+		{
+			name: "opening mismatch",
+			proof: func() *Proof {
+				proof := NewProofer(graph).CreateProof(5)
+				proof.edgeValues[3][1] = proof.edgeValues[3][1] + "x"
+				return proof
+			},
+			expected: ErrOpeningMismatch,
+			round:    3,
+		},
+		{
+			name: "malformed commitment",
+			proof: func() *Proof {
+				proof := NewProofer(graph).CreateProof(5)
+				proof.commitementGraphs[4] = []byte("invalid")
+				return proof
+			},
+			expected: ErrMalformedCommitment,
+			round:    4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := tt.proof().VerifyDetailed()
+			assert.Nil(t, report)
+			assert.ErrorIs(t, err, tt.expected)
+
+			var roundErr *RoundError
+			require.ErrorAs(t, err, &roundErr)
+			assert.Equal(t, tt.round, roundErr.Round)
+		})
+	}
+}
+
+func TestVerifyDetailedProofErrors(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+
+	proof := NewProofer(graph, WithHashAlgorithm(hashing.SHA1)).CreateProof(3)
+	_, err := proof.VerifyDetailed()
+	assert.ErrorIs(t, err, ErrHashNotAllowed)
+
+	proof = NewProofer(graph).CreateProof(3)
+	other := publicGraph(graph)
+	other.AddNode(coloringgraph.ColorNodeValue(""))
+	_, err = proof.VerifyStatementDetailed(other)
+	assert.ErrorIs(t, err, ErrStatementMismatch)
+	_, err = proof.VerifyStatementDetailed(nil)
+	assert.ErrorIs(t, err, ErrStatementMismatch)
+
+	proof.edgeIds = proof.edgeIds[:1]
+	_, err = proof.VerifyDetailed()
+	assert.ErrorIs(t, err, ErrMalformedProof)
+}
+
+func TestVerifyStatementNormalization(t *testing.T) {
+	// A triangle with one edge repeated in the other direction
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddEdge(1, 0)
+	graph.AddEdge(0, 1)
+	graph.AddEdge(2, 1)
+	graph.AddEdge(0, 2)
+
+	proof := NewProofer(graph, WithNormalization()).CreateProof(10)
+	assert.Len(t, graph.GetEdges(), 4, "the proofer must not modify the graph")
+
+	report, err := proof.VerifyDetailed()
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Edges)
+
+	// The fingerprint ignores repeated edges, so the original graph is the statement
+	public := publicGraph(graph)
+	assert.True(t, proof.VerifyStatement(public))
+}
+
+func TestVerifyStatementIgnoresEdgeOrder(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(2, 3)
+	graph.AddEdge(3, 0)
+	graph.AddEdge(0, 2)
+
+	proof := NewProofer(graph).CreateProof(10)
+
+	// The verifier built the same graph with its edges in another order and direction
+	public := coloringgraph.NewColoringGraph()
+	for range 4 {
+		public.AddNode(coloringgraph.ColorNodeValue(""))
+	}
+	public.AddEdge(2, 0)
+	public.AddEdge(0, 3)
+	public.AddEdge(3, 2)
+	public.AddEdge(1, 0)
+	public.AddEdge(2, 1)
+
+	report, err := proof.VerifyStatementDetailed(public)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Nodes)
+	assert.Equal(t, 5, report.Edges)
+
+	// One more node changes the statement
+	public.AddNode(coloringgraph.ColorNodeValue(""))
+	_, err = proof.VerifyStatementDetailed(public)
+	assert.ErrorIs(t, err, ErrStatementMismatch)
+}
@@ -1,54 +1,240 @@
 package zkp
 
 import (
-	"crypto/sha1"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
 
 	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
 	commitmentgraph "github.com/hvuhsg/zkp/commitment_graph"
+	"github.com/hvuhsg/zkp/graph"
+	"github.com/hvuhsg/zkp/hashing"
 )
 
+var (
+	ErrInvalidWitness = errors.New("coloring is not a valid witness")
+	ErrInvalidLength  = errors.New("proof length must be positive")
+)
+
+// WitnessError lists what is wrong with the coloring given to a Proofer.
+type WitnessError struct {
+	Violations []coloringgraph.ColoringViolation
+}
+
+func (e *WitnessError) Error() string {
+	msg := fmt.Sprintf("%v: %v", ErrInvalidWitness, e.Violations[0])
+	if len(e.Violations) > 1 {
+		msg += fmt.Sprintf(" and %d more", len(e.Violations)-1)
+	}
+	return msg
+}
+
+func (e *WitnessError) Unwrap() error {
+	return ErrInvalidWitness
+}
+
 type Proofer struct {
-	coloredGraph *coloringgraph.ColoringGraph
+	coloredGraph  *coloringgraph.ColoringGraph
+	hashAlgorithm hashing.Algorithm
+	entropy       io.Reader
+	concurrency   int
+	normalize     bool
+	unchecked     bool
+}
+
+// ProoferOption configures a Proofer.
+type ProoferOption func(*Proofer)
+
+// WithHashAlgorithm sets the algorithm used for the commitments.
+// The default is hashing.Default.
+func WithHashAlgorithm(alg hashing.Algorithm) ProoferOption {
+	return func(p *Proofer) {
+		p.hashAlgorithm = alg
+	}
+}
+
+// WithEntropy sets the entropy source of the proof. The default is
+// crypto/rand.Reader.
+//
+// The source is only read for one 32-byte seed per round. The color
+// permutation and the commitment salts of a round are drawn from a
+// math/rand/v2 ChaCha8 stream keyed by its seed, so the 256-bit seed is the
+// entropy of the round.
+func WithEntropy(r io.Reader) ProoferOption {
+	return func(p *Proofer) {
+		p.entropy = r
+	}
+}
+
+// WithConcurrency sets the number of rounds built at the same time.
+// A value below 1 means runtime.GOMAXPROCS(0), which is the default.
+//
+// The proof only depends on the entropy stream and not on the concurrency:
+// every round reads its own seed from the entropy source in order.
+func WithConcurrency(n int) ProoferOption {
+	return func(p *Proofer) {
+		p.concurrency = n
+	}
+}
+
+// WithNormalization makes the proofer prove a normalized copy of the graph:
+// every edge is written with its smaller index first and repeated edges are
+// dropped, see graph.Graph.Normalize. The graph passed to NewProofer is not
+// modified, and the proof verifies against it as the public graph.
+func WithNormalization() ProoferOption {
+	return func(p *Proofer) {
+		p.normalize = true
+	}
+}
+
+// WithoutInputChecks turns off the checks Prove runs before building a proof,
+// restoring the behavior of earlier versions: an invalid coloring or a graph
+// with a self-loop or a repeated edge gives a proof that does not verify, a
+// length below 1 gives a proof without rounds, and a graph without edges makes
+// CreateProof panic.
+// It is meant for testing verifiers against bad proofs.
+func WithoutInputChecks() ProoferOption {
+	return func(p *Proofer) {
+		p.unchecked = true
+	}
 }
 
 type CommitementGraphPayload []byte
 
-func (cgp CommitementGraphPayload) Hash() [20]byte {
-	return sha1.Sum(cgp)
+func (cgp CommitementGraphPayload) Hash(alg hashing.Algorithm) []byte {
+	return alg.Sum(cgp)
 }
 
 type Proof struct {
+	hashAlgorithm     hashing.Algorithm
+	statement         []byte
 	commitementGraphs []CommitementGraphPayload
 	edgeIds           []uint64
 	edgeValues        [][2]string
 }
 
-func NewProofer(coloredGraph *coloringgraph.ColoringGraph) *Proofer {
-	return &Proofer{
-		coloredGraph: coloredGraph,
+// HashAlgorithm returns the algorithm the commitments of the proof use.
+func (p *Proof) HashAlgorithm() hashing.Algorithm {
+	return p.hashAlgorithm
+}
+
+func NewProofer(coloredGraph *coloringgraph.ColoringGraph, opts ...ProoferOption) *Proofer {
+	p := &Proofer{
+		coloredGraph:  coloredGraph,
+		hashAlgorithm: hashing.Default,
+		entropy:       rand.Reader,
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	if p.concurrency < 1 {
+		p.concurrency = runtime.GOMAXPROCS(0)
+	}
+	if p.normalize {
+		p.coloredGraph = p.coloredGraph.Clone()
+		p.coloredGraph.Normalize()
+	}
+	return p
 }
 
+// CheckWitness checks the coloring before spending time on a proof that would
+// not verify. It returns a *WitnessError, which wraps ErrInvalidWitness, when
+// two adjacent nodes have the same color, an edge points to a missing node, or
+// a node has a color outside of the graph's Colors. Graphs with an empty Colors
+// set are not checked against it.
+func (p *Proofer) CheckWitness() error {
+	violations := p.coloredGraph.Check()
+	if len(p.coloredGraph.Colors) == 0 {
+		violations = slices.DeleteFunc(violations, func(v coloringgraph.ColoringViolation) bool {
+			return v.Kind == coloringgraph.UnknownColor
+		})
+	}
+	if len(violations) > 0 {
+		return &WitnessError{Violations: violations}
+	}
+	return nil
+}
+
+// CreateProof creates a proof with the given number of rounds.
+// It panics if the input is rejected or the entropy source fails, see Prove.
 func (p *Proofer) CreateProof(length int) *Proof {
-	commitementGraphsPayloads := make([]CommitementGraphPayload, length)
-	commitementGraphs := make([]*commitmentgraph.CommitmentGraph, length)
-	edgeValues := make([][2]string, length)
-	edgeIds := make([]uint64, length)
+	proof, err := p.Prove(length)
+	if err != nil {
+		panic(err)
+	}
+	return proof
+}
 
-	for i := range commitementGraphsPayloads {
-		cg := commitmentgraph.NewCommitmentGraph(p.coloredGraph)
-		commitementGraphsPayloads[i] = cg.Serialize()
-		commitementGraphs[i] = cg
+// Prove creates a proof with the given number of rounds. Unless the proofer
+// has WithoutInputChecks, it first rejects inputs that can not give a valid
+// proof, returning:
+//
+//   - ErrNoEdges when the graph has no edges,
+//   - ErrInvalidLength when length is below 1,
+//   - an error wrapping graph.ErrSelfLoop when an edge connects a node to
+//     itself,
+//   - an error wrapping graph.ErrDuplicateEdge when an edge repeats an earlier
+//     edge, see WithNormalization,
+//   - a *WitnessError wrapping ErrInvalidWitness when the coloring is not
+//     valid, see CheckWitness.
+//
+// An error wrapping hashing.ErrUnknownAlgorithm is returned, even with
+// WithoutInputChecks, when the hash algorithm is not registered. Otherwise the
+// only error is a failure of the entropy source.
+func (p *Proofer) Prove(length int) (*Proof, error) {
+	if !p.hashAlgorithm.Available() {
+		return nil, fmt.Errorf("%w: %v", hashing.ErrUnknownAlgorithm, p.hashAlgorithm)
 	}
+	if !p.unchecked {
+		if err := p.checkInput(length); err != nil {
+			return nil, err
+		}
+	}
+	// Without the input checks a negative length gives no rounds, like 0
+	return p.createProof(max(length, 0))
+}
 
-	newRandomizer := NewRandomizerFromCommitments(commitementGraphsPayloads)
+func (p *Proofer) checkInput(length int) error {
+	edges := p.coloredGraph.GetEdges()
+	if len(edges) == 0 {
+		return ErrNoEdges
+	}
+	if length < 1 {
+		return fmt.Errorf("%w: %d rounds", ErrInvalidLength, length)
+	}
+	seen := make(map[[2]int]int, len(edges))
+	for i, edge := range edges {
+		if edge.From == edge.To {
+			return fmt.Errorf("%w: edge %d (%d, %d)", graph.ErrSelfLoop, i, edge.From, edge.To)
+		}
+		key := [2]int{min(edge.From, edge.To), max(edge.From, edge.To)}
+		if first, ok := seen[key]; ok {
+			return fmt.Errorf("%w: edge %d (%d, %d) repeats edge %d, see WithNormalization", graph.ErrDuplicateEdge, i, edge.From, edge.To, first)
+		}
+		seen[key] = i
+	}
+	return p.CheckWitness()
+}
 
-	for i, cg := range commitementGraphs {
-		edgeNonce := newRandomizer.Uint64()
-		edges := cg.GetEdges()
+func (p *Proofer) createProof(length int) (*Proof, error) {
+	commitementGraphsPayloads, commitementGraphs, err := p.createCommitments(length)
+	if err != nil {
+		return nil, err
+	}
+	edgeValues := make([][2]string, length)
+	edgeIds := make([]uint64, length)
+
+	statement := statementFingerprint(p.coloredGraph.Graph)
+	challenges := edgeChallenges(p.hashAlgorithm, statement, commitementGraphsPayloads, len(p.coloredGraph.GetEdges()))
 
-		edgeId := edgeNonce % uint64(len(edges))
-		edge := edges[edgeId]
+	for i, cg := range commitementGraphs {
+		edgeId := challenges[i]
+		edge := cg.GetEdges()[edgeId]
 		nodev1 := cg.GetNodeValue(edge.From)
 		nodev2 := cg.GetNodeValue(edge.To)
 
@@ -57,8 +243,61 @@ func (p *Proofer) CreateProof(length int) *Proof {
 	}
 
 	return &Proof{
+		hashAlgorithm:     p.hashAlgorithm,
+		statement:         statement,
 		commitementGraphs: commitementGraphsPayloads,
 		edgeValues:        edgeValues,
 		edgeIds:           edgeIds,
+	}, nil
+}
+
+// createCommitments builds the commitment graphs of all rounds with a pool of
+// workers. Every round gets a seed read in order from the entropy source and
+// draws its randomness from a ChaCha8 stream keyed by that seed, so the result
+// does not depend on how rounds are scheduled.
+func (p *Proofer) createCommitments(length int) ([]CommitementGraphPayload, []*commitmentgraph.CommitmentGraph, error) {
+	seeds := make([][32]byte, length)
+	for i := range seeds {
+		if _, err := io.ReadFull(p.entropy, seeds[i][:]); err != nil {
+			return nil, nil, fmt.Errorf("failed to read round seed: %w", err)
+		}
+	}
+
+	payloads := make([]CommitementGraphPayload, length)
+	commitementGraphs := make([]*commitmentgraph.CommitmentGraph, length)
+	errs := make([]error, length)
+
+	rounds := make(chan int)
+	var wg sync.WaitGroup
+	for range min(p.concurrency, length) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				cg, err := commitmentgraph.NewCommitmentGraphWithOptions(p.coloredGraph, commitmentgraph.Options{
+					Hash: p.hashAlgorithm,
+					Rand: mathrand.NewChaCha8(seeds[i]),
+				})
+				if err != nil {
+					errs[i] = fmt.Errorf("failed to create commitment graph: %w", err)
+					continue
+				}
+				payloads[i] = cg.Serialize()
+				commitementGraphs[i] = cg
+			}
+		}()
+	}
+
+	for i := range length {
+		rounds <- i
+	}
+	close(rounds)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
 	}
+	return payloads, commitementGraphs, nil
 }
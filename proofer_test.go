@@ -1,10 +1,15 @@
 package zkp
 
 import (
+	"bytes"
+	"math/rand/v2"
+	"runtime"
 	"strings"
 	"testing"
 
 	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/graph"
+	"github.com/hvuhsg/zkp/hashing"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,28 +58,222 @@ func TestCreateProof(t *testing.T) {
 	}
 }
 
-func TestHashModMaxUint64(t *testing.T) {
-	// Test with a known hash value
-	testHash := [20]byte{
-		1, 2, 3, 4, 5, 6, 7, 8, 9, 10,
-		11, 12, 13, 14, 15, 16, 17, 18, 19, 20,
-	}
-	result := hashModMaxUint64(testHash)
-	assert.NotZero(t, result, "Hash should not be zero")
-}
-
 func TestCommitmentGraphPayloadHash(t *testing.T) {
 	// Test with a simple payload
 	payload := CommitementGraphPayload([]byte("test"))
-	hash := payload.Hash()
+	hash := payload.Hash(hashing.SHA256)
 	assert.NotZero(t, hash, "Hash should not be zero")
 
 	// Test that same payload produces same hash
-	hash2 := payload.Hash()
+	hash2 := payload.Hash(hashing.SHA256)
 	assert.Equal(t, hash, hash2, "Same payload should produce same hash")
 
 	// Test that different payloads produce different hashes
 	payload2 := CommitementGraphPayload([]byte("test2"))
-	hash3 := payload2.Hash()
+	hash3 := payload2.Hash(hashing.SHA256)
 	assert.NotEqual(t, hash, hash3, "Different payloads should produce different hashes")
 }
+
+func TestCreateProofWithEntropy(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(0, 2)
+
+	var seed [32]byte
+	seed[0] = 42
+
+	// The same entropy stream gives the same proof
+	proof1 := NewProofer(graph, WithEntropy(rand.NewChaCha8(seed))).CreateProof(5)
+	proof2 := NewProofer(graph, WithEntropy(rand.NewChaCha8(seed))).CreateProof(5)
+	assert.Equal(t, proof1, proof2)
+	assert.True(t, proof1.Verify())
+
+	// A different entropy stream gives a different proof
+	seed[0] = 43
+	proof3 := NewProofer(graph, WithEntropy(rand.NewChaCha8(seed))).CreateProof(5)
+	assert.NotEqual(t, proof1.commitementGraphs, proof3.commitementGraphs)
+	assert.True(t, proof3.Verify())
+
+	// A failing entropy source is reported
+	_, err := NewProofer(graph, WithEntropy(bytes.NewReader(nil))).createProof(5)
+	assert.Error(t, err)
+	assert.Panics(t, func() {
+		NewProofer(graph, WithEntropy(bytes.NewReader(nil))).CreateProof(5)
+	})
+}
+
+func TestCreateProofConcurrencyIsDeterministic(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	colors := []string{"red", "blue", "green"}
+	for i := range 30 {
+		graph.AddNode(coloringgraph.ColorNodeValue(colors[i%3]))
+		if i > 0 {
+			graph.AddEdge(i-1, i)
+		}
+	}
+
+	var seed [32]byte
+	seed[0] = 8
+
+	sequential := NewProofer(graph, WithEntropy(rand.NewChaCha8(seed)), WithConcurrency(1)).CreateProof(50)
+	expected, err := sequential.MarshalBinary()
+	assert.NoError(t, err)
+	assert.True(t, sequential.Verify())
+
+	for _, concurrency := range []int{2, 7, 64, 0} {
+		proof := NewProofer(graph, WithEntropy(rand.NewChaCha8(seed)), WithConcurrency(concurrency)).CreateProof(50)
+		data, err := proof.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, data, "concurrency %d should give the same proof", concurrency)
+	}
+}
+
+func TestWithConcurrencyDefault(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+
+	assert.Equal(t, runtime.GOMAXPROCS(0), NewProofer(graph).concurrency)
+	assert.Equal(t, runtime.GOMAXPROCS(0), NewProofer(graph, WithConcurrency(-1)).concurrency)
+	assert.Equal(t, 3, NewProofer(graph, WithConcurrency(3)).concurrency)
+}
+
+func TestCheckWitness(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+
+	// Without a declared palette only the edges are checked
+	assert.NoError(t, NewProofer(graph).CheckWitness())
+
+	graph.AddEdge(2, 0)
+	err := NewProofer(graph).CheckWitness()
+	assert.ErrorIs(t, err, ErrInvalidWitness)
+	var witnessErr *WitnessError
+	if assert.ErrorAs(t, err, &witnessErr) {
+		assert.Equal(t, []coloringgraph.ColoringViolation{
+			{Kind: coloringgraph.ConflictingEdge, Edge: 2, From: 2, To: 0, Color: "red"},
+		}, witnessErr.Violations)
+	}
+	assert.EqualError(t, err, `coloring is not a valid witness: edge 2 (2, 0) has color "red" on both ends`)
+
+	graph.Colors = map[string]struct{}{"blue": {}}
+	err = NewProofer(graph).CheckWitness()
+	assert.ErrorIs(t, err, ErrInvalidWitness)
+	assert.ErrorContains(t, err, `node 0 has unknown color "red" and 2 more`)
+}
+
+func TestProveRejectsDegenerateInputs(t *testing.T) {
+	triangle := func(colors ...string) *coloringgraph.ColoringGraph {
+		graph := coloringgraph.NewColoringGraph()
+		for _, color := range colors {
+			graph.AddNode(coloringgraph.ColorNodeValue(color))
+		}
+		graph.AddEdge(0, 1)
+		graph.AddEdge(1, 2)
+		graph.AddEdge(0, 2)
+		return graph
+	}
+
+	noEdges := coloringgraph.NewColoringGraph()
+	noEdges.AddNode(coloringgraph.ColorNodeValue("red"))
+
+	selfLoop := triangle("red", "blue", "green")
+	selfLoop.AddEdge(1, 1)
+
+	duplicate := triangle("red", "blue", "green")
+	duplicate.AddEdge(1, 0)
+
+	tests := []struct {
+		name     string
+		graph    *coloringgraph.ColoringGraph
+		length   int
+		expected error
+	}{
+		{name: "no edges", graph: noEdges, length: 5, expected: ErrNoEdges},
+		{name: "empty graph", graph: coloringgraph.NewColoringGraph(), length: 5, expected: ErrNoEdges},
+		{name: "zero length", graph: triangle("red", "blue", "green"), length: 0, expected: ErrInvalidLength},
+		{name: "negative length", graph: triangle("red", "blue", "green"), length: -3, expected: ErrInvalidLength},
+		{name: "self-loop", graph: selfLoop, length: 5, expected: graph.ErrSelfLoop},
+		{name: "duplicate edge", graph: duplicate, length: 5, expected: graph.ErrDuplicateEdge},
+		{name: "invalid coloring", graph: triangle("red", "blue", "red"), length: 5, expected: ErrInvalidWitness},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, err := NewProofer(tt.graph).Prove(tt.length)
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Nil(t, proof)
+
+			assert.PanicsWithError(t, err.Error(), func() {
+				NewProofer(tt.graph).CreateProof(tt.length)
+			})
+		})
+	}
+
+	_, err := NewProofer(triangle("red", "blue", "green")).ProveWithSoundness(0)
+	assert.ErrorIs(t, err, ErrInvalidLength)
+	_, err = NewProofer(noEdges).ProveWithSoundness(128)
+	assert.ErrorIs(t, err, ErrNoEdges)
+}
+
+func TestProve(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+
+	proof, err := NewProofer(graph).Prove(5)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify())
+
+	proof, err = NewProofer(graph).ProveWithSoundness(20)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify())
+}
+
+func TestProveRejectsUnknownHashAlgorithm(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddEdge(0, 1)
+
+	for _, opts := range [][]ProoferOption{
+		{WithHashAlgorithm(hashing.Algorithm(99))},
+		{WithHashAlgorithm(hashing.Algorithm(99)), WithoutInputChecks()},
+	} {
+		proof, err := NewProofer(graph, opts...).Prove(3)
+		assert.ErrorIs(t, err, hashing.ErrUnknownAlgorithm)
+		assert.Nil(t, proof)
+	}
+}
+
+func TestWithoutInputChecks(t *testing.T) {
+	invalid := coloringgraph.NewColoringGraph()
+	invalid.AddNode(coloringgraph.ColorNodeValue("red"))
+	invalid.AddNode(coloringgraph.ColorNodeValue("red"))
+	invalid.AddEdge(0, 1)
+	invalid.AddEdge(1, 1)
+
+	// The proof is built and only fails verification
+	proof, err := NewProofer(invalid, WithoutInputChecks()).Prove(5)
+	assert.NoError(t, err)
+	assert.False(t, proof.Verify())
+
+	for _, length := range []int{0, -1} {
+		proof, err = NewProofer(invalid, WithoutInputChecks()).Prove(length)
+		assert.NoError(t, err)
+		assert.Empty(t, proof.edgeIds)
+	}
+
+	noEdges := coloringgraph.NewColoringGraph()
+	noEdges.AddNode(coloringgraph.ColorNodeValue("red"))
+	assert.Panics(t, func() {
+		NewProofer(noEdges, WithoutInputChecks()).CreateProof(1)
+	})
+}
@@ -0,0 +1,168 @@
+package zkp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	commitmentgraph "github.com/hvuhsg/zkp/commitment_graph"
+	graph "github.com/hvuhsg/zkp/graph"
+	"github.com/hvuhsg/zkp/hashing"
+)
+
+// bitsPerRound returns -log2 of the probability that a single round misses a
+// cheating prover. A coloring that is not proper has at least one bad edge, so
+// a round catches it with probability at least 1/edgesCount.
+func bitsPerRound(edgesCount int) float64 {
+	return -math.Log1p(-1/float64(edgesCount)) / math.Ln2
+}
+
+// RoundsForSoundness returns the number of rounds needed so that a prover
+// without a proper coloring is accepted with probability at most 2^-bits.
+func RoundsForSoundness(edgesCount int, bits int) int {
+	if edgesCount <= 0 || bits <= 0 {
+		return 0
+	}
+
+	// With a single edge every round opens the bad edge
+	if edgesCount == 1 {
+		return 1
+	}
+
+	return int(math.Ceil(float64(bits) / bitsPerRound(edgesCount)))
+}
+
+// SoundnessBits returns the soundness level reached by the given number of
+// rounds: a cheating prover is accepted with probability at most 2^-bits.
+func SoundnessBits(edgesCount int, rounds int) float64 {
+	if edgesCount <= 0 || rounds <= 0 {
+		return 0
+	}
+	if edgesCount == 1 {
+		return math.Inf(1)
+	}
+	return float64(rounds) * bitsPerRound(edgesCount)
+}
+
+// CreateProofWithSoundness creates a proof with enough rounds for a cheating
+// prover to be accepted with probability at most 2^-bits.
+// It panics if the input is rejected or the entropy source fails, see Prove.
+func (p *Proofer) CreateProofWithSoundness(bits int) *Proof {
+	return p.CreateProof(RoundsForSoundness(len(p.coloredGraph.GetEdges()), bits))
+}
+
+// ProveWithSoundness is like CreateProofWithSoundness but returns an error
+// instead of panicking, see Prove. A bits value below 1 gives no rounds and
+// ErrInvalidLength.
+func (p *Proofer) ProveWithSoundness(bits int) (*Proof, error) {
+	return p.Prove(RoundsForSoundness(len(p.coloredGraph.GetEdges()), bits))
+}
+
+// ProofPlan describes the cost of a proof before it is created.
+type ProofPlan struct {
+	Rounds        int
+	SoundnessBits float64
+
+	// ProofSize is the size of the binary encoding of the proof in bytes.
+	// It is exact when all colors have names of the same length.
+	ProofSize int
+
+	// ProveTime and VerifyTime are extrapolated from a single timed round.
+	// ProveTime assumes the rounds are spread evenly over the workers,
+	// VerifyTime is for a verifier checking one round at a time.
+	ProveTime  time.Duration
+	VerifyTime time.Duration
+}
+
+// Plan estimates the cost of a proof with the given soundness level.
+//
+// A single throwaway round is built with crypto/rand to time the prover and
+// the verifier, the entropy source of the proofer is not used. Plan returns an
+// error wrapping hashing.ErrUnknownAlgorithm when the hash algorithm of the
+// proofer is not registered.
+func (p *Proofer) Plan(bits int) (ProofPlan, error) {
+	if !p.hashAlgorithm.Available() {
+		return ProofPlan{}, fmt.Errorf("%w: %v", hashing.ErrUnknownAlgorithm, p.hashAlgorithm)
+	}
+
+	edgesCount := len(p.coloredGraph.GetEdges())
+	rounds := RoundsForSoundness(edgesCount, bits)
+
+	plan := ProofPlan{
+		Rounds:        rounds,
+		SoundnessBits: SoundnessBits(edgesCount, rounds),
+		ProofSize:     p.estimateProofSize(rounds),
+	}
+
+	proveTime, verifyTime := p.timeRound()
+	if rounds > 0 {
+		workers := min(p.concurrency, rounds)
+		plan.ProveTime = proveTime * time.Duration((rounds+workers-1)/workers)
+	}
+	plan.VerifyTime = verifyTime * time.Duration(rounds)
+
+	return plan, nil
+}
+
+func (p *Proofer) estimateProofSize(rounds int) int {
+	hashSize := p.hashAlgorithm.Size()
+
+	// A commitment graph has the structure of the colored graph and a hex
+	// encoded hash in every node
+	placeholder := p.coloredGraph.Clone()
+	commitment := coloringgraph.ColorNodeValue(strings.Repeat("0", 2*hashSize))
+	for _, node := range placeholder.GetNodes() {
+		node.Value = commitment
+	}
+	commitmentSize := len(placeholder.Serialize())
+
+	// Every opened value is a color, a separator and a salt. The color of a
+	// node is mapped to a random color, so use the average color name length.
+	colors := make(map[coloringgraph.ColorNodeValue]struct{})
+	colorsLength := 0
+	for _, node := range p.coloredGraph.GetNodes() {
+		if _, ok := colors[node.Value]; !ok {
+			colors[node.Value] = struct{}{}
+			colorsLength += len(node.Value)
+		}
+	}
+	openingSize := 2 * (1 + commitmentgraph.SaltLength)
+	if len(colors) > 0 {
+		openingSize += int(math.Round(2 * float64(colorsLength) / float64(len(colors))))
+	}
+
+	// [version][hash_algorithm][statement_size][statement][rounds_count]
+	headerSize := 1 + 1 + 2 + len(graph.Fingerprint{}) + 4
+	// [commitment_size][commitment][edge_id][value1_size][value1][value2_size][value2]
+	roundSize := 4 + commitmentSize + 8 + 2 + 2 + openingSize
+
+	return headerSize + rounds*roundSize
+}
+
+// timeRound times the creation and the verification of a single commitment
+// graph.
+func (p *Proofer) timeRound() (time.Duration, time.Duration) {
+	start := time.Now()
+	cg, err := commitmentgraph.NewCommitmentGraphWithOptions(p.coloredGraph, commitmentgraph.Options{
+		Hash: p.hashAlgorithm,
+		Rand: rand.Reader,
+	})
+	if err != nil {
+		return 0, 0
+	}
+	payload := cg.Serialize()
+	proveTime := time.Since(start)
+
+	start = time.Now()
+	decoded, err := graph.DeserializeGraph(payload, coloringgraph.DeserializeColorNodeValue)
+	if err == nil {
+		sameStructure(p.coloredGraph.Graph, decoded)
+		p.hashAlgorithm.Sum(payload)
+	}
+	verifyTime := time.Since(start)
+
+	return proveTime, verifyTime
+}
@@ -0,0 +1,97 @@
+package zkp
+
+import (
+	"math"
+	"testing"
+
+	coloringgraph "github.com/hvuhsg/zkp/coloring_graph"
+	"github.com/hvuhsg/zkp/hashing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundsForSoundness(t *testing.T) {
+	tests := []struct {
+		name       string
+		edgesCount int
+		bits       int
+		expected   int
+	}{
+		{name: "no edges", edgesCount: 0, bits: 128, expected: 0},
+		{name: "no soundness", edgesCount: 10, bits: 0, expected: 0},
+		{name: "single edge", edgesCount: 1, bits: 128, expected: 1},
+		{name: "two edges", edgesCount: 2, bits: 10, expected: 10},
+		{name: "triangle", edgesCount: 3, bits: 1, expected: 2},
+		{name: "triangle 128 bits", edgesCount: 3, bits: 128, expected: 219},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RoundsForSoundness(tt.edgesCount, tt.bits))
+		})
+	}
+}
+
+func TestRoundsForSoundnessIsMinimal(t *testing.T) {
+	for _, edgesCount := range []int{2, 3, 7, 100, 10000} {
+		for _, bits := range []int{1, 40, 80, 128} {
+			rounds := RoundsForSoundness(edgesCount, bits)
+			assert.GreaterOrEqual(t, SoundnessBits(edgesCount, rounds), float64(bits))
+			assert.Less(t, SoundnessBits(edgesCount, rounds-1), float64(bits))
+		}
+	}
+
+	assert.True(t, math.IsInf(SoundnessBits(1, 1), 1))
+	assert.Zero(t, SoundnessBits(10, 0))
+}
+
+func TestCreateProofWithSoundness(t *testing.T) {
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blue"))
+	graph.AddNode(coloringgraph.ColorNodeValue("green"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(0, 2)
+
+	proof := NewProofer(graph).CreateProofWithSoundness(20)
+	assert.Len(t, proof.commitementGraphs, RoundsForSoundness(3, 20))
+	assert.True(t, proof.VerifyStatement(publicGraph(graph)))
+}
+
+func TestPlan(t *testing.T) {
+	// Colors of the same length make the size estimate exact
+	graph := coloringgraph.NewColoringGraph()
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddNode(coloringgraph.ColorNodeValue("blu"))
+	graph.AddNode(coloringgraph.ColorNodeValue("grn"))
+	graph.AddNode(coloringgraph.ColorNodeValue("red"))
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(0, 2)
+	graph.AddEdge(2, 3)
+
+	proofer := NewProofer(graph)
+	plan, err := proofer.Plan(40)
+	require.NoError(t, err)
+
+	assert.Equal(t, RoundsForSoundness(4, 40), plan.Rounds)
+	assert.GreaterOrEqual(t, plan.SoundnessBits, 40.0)
+	assert.Positive(t, plan.ProveTime)
+	assert.Positive(t, plan.VerifyTime)
+
+	data, err := proofer.CreateProof(plan.Rounds).MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, len(data), plan.ProofSize)
+
+	// The statement is a SHA-256 fingerprint whatever the commitment hash
+	proofer = NewProofer(graph, WithHashAlgorithm(hashing.SHA1))
+	plan, err = proofer.Plan(40)
+	require.NoError(t, err)
+	data, err = proofer.CreateProof(plan.Rounds).MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, len(data), plan.ProofSize)
+
+	_, err = NewProofer(graph, WithHashAlgorithm(hashing.Algorithm(99))).Plan(40)
+	assert.ErrorIs(t, err, hashing.ErrUnknownAlgorithm)
+}
@@ -0,0 +1,46 @@
+package zkp
+
+import (
+	graph "github.com/hvuhsg/zkp/graph"
+)
+
+// statementFingerprint returns the statement a proof about g is bound to:
+// the graph's canonical fingerprint. Node values are not included, so the
+// fingerprint of a colored graph and of its uncolored public version are the
+// same, and neither are the order and direction of the edges.
+func statementFingerprint[T graph.NodeValue](g *graph.Graph[T]) []byte {
+	fingerprint := g.Fingerprint()
+	return fingerprint[:]
+}
+
+// distinctEdges returns the number of edges of g that do not repeat an earlier
+// edge in either direction, which is the number of edges the fingerprint of g
+// covers.
+func distinctEdges[T graph.NodeValue](g *graph.Graph[T]) int {
+	seen := make(map[[2]int]struct{}, len(g.GetEdges()))
+	for _, edge := range g.GetEdges() {
+		seen[[2]int{min(edge.From, edge.To), max(edge.From, edge.To)}] = struct{}{}
+	}
+	return len(seen)
+}
+
+// sameStructure reports whether both graphs have the same number of nodes and
+// exactly the same edges in the same order.
+func sameStructure[A, B graph.NodeValue](a *graph.Graph[A], b *graph.Graph[B]) bool {
+	if len(a.GetNodes()) != len(b.GetNodes()) {
+		return false
+	}
+
+	aEdges := a.GetEdges()
+	bEdges := b.GetEdges()
+	if len(aEdges) != len(bEdges) {
+		return false
+	}
+
+	for i, edge := range aEdges {
+		if edge.From != bEdges[i].From || edge.To != bEdges[i].To {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package zkp
+
+import (
+	"crypto/sha3"
+	"encoding/binary"
+	"math"
+
+	"github.com/hvuhsg/zkp/hashing"
+)
+
+const (
+	transcriptCustomization = "github.com/hvuhsg/zkp transcript"
+	proofDomain             = "graph-coloring proof v1"
+)
+
+// Transcript is a Fiat-Shamir transcript built on the SHAKE256 XOF.
+//
+// Every message is absorbed together with its label and length, so two
+// different sequences of messages never lead to the same state. Challenges are
+// squeezed from a copy of the state and then absorbed back, so every challenge
+// depends on everything absorbed and squeezed before it.
+type Transcript struct {
+	state *sha3.SHAKE
+}
+
+// NewTranscript creates a transcript separated from any other protocol by the
+// given domain.
+func NewTranscript(domain string) *Transcript {
+	t := &Transcript{state: newTranscriptState()}
+	t.AppendMessage("domain", []byte(domain))
+	return t
+}
+
+// AppendMessage absorbs a labeled message into the transcript.
+func (t *Transcript) AppendMessage(label string, message []byte) {
+	t.writeLength(len(label))
+	t.state.Write([]byte(label))
+	t.writeLength(len(message))
+	t.state.Write(message)
+}
+
+// AppendUint64 absorbs a labeled integer into the transcript.
+func (t *Transcript) AppendUint64(label string, value uint64) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, value)
+	t.AppendMessage(label, buf)
+}
+
+// ChallengeBytes squeezes n labeled challenge bytes from the transcript.
+func (t *Transcript) ChallengeBytes(label string, n int) []byte {
+	t.AppendUint64(label, uint64(n))
+
+	fork, err := t.fork()
+	if err != nil {
+		// The state of a SHAKE that was never read from can always be copied
+		panic(err)
+	}
+
+	challenge := make([]byte, n)
+	fork.Read(challenge)
+
+	t.AppendMessage("challenge", challenge)
+	return challenge
+}
+
+// ChallengeIndex squeezes a labeled challenge uniformly distributed in [0, n).
+// Values that would bias the result towards small indices are rejected and a
+// new value is squeezed instead.
+func (t *Transcript) ChallengeIndex(label string, n uint64) uint64 {
+	if n == 0 {
+		panic("challenge range is empty")
+	}
+
+	// The largest multiple of n that fits in a uint64
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		value := binary.BigEndian.Uint64(t.ChallengeBytes(label, 8))
+		if value < limit {
+			return value % n
+		}
+	}
+}
+
+func newTranscriptState() *sha3.SHAKE {
+	return sha3.NewCSHAKE256(nil, []byte(transcriptCustomization))
+}
+
+func (t *Transcript) writeLength(length int) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(length))
+	t.state.Write(buf)
+}
+
+func (t *Transcript) fork() (*sha3.SHAKE, error) {
+	state, err := t.state.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	fork := newTranscriptState()
+	if err := fork.UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
+
+// newProofTranscript starts the transcript of a proof by absorbing the hash
+// algorithm, the statement and every round's commitment.
+func newProofTranscript(alg hashing.Algorithm, statement []byte, commitments []CommitementGraphPayload) *Transcript {
+	t := NewTranscript(proofDomain)
+	t.AppendMessage("hash", []byte{byte(alg)})
+	t.AppendMessage("statement", statement)
+	t.AppendUint64("rounds", uint64(len(commitments)))
+	for _, commitment := range commitments {
+		t.AppendMessage("commitment", commitment)
+	}
+	return t
+}
+
+// edgeChallenges derives the edge each round has to open.
+func edgeChallenges(alg hashing.Algorithm, statement []byte, commitments []CommitementGraphPayload, edgesCount int) []uint64 {
+	t := newProofTranscript(alg, statement, commitments)
+
+	challenges := make([]uint64, len(commitments))
+	for i := range challenges {
+		challenges[i] = t.ChallengeIndex("edge", uint64(edgesCount))
+	}
+	return challenges
+}
@@ -0,0 +1,76 @@
+package zkp
+
+import (
+	"testing"
+
+	"github.com/hvuhsg/zkp/hashing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscriptDeterministic(t *testing.T) {
+	t1 := NewTranscript("test")
+	t1.AppendMessage("message", []byte("hello"))
+	t2 := NewTranscript("test")
+	t2.AppendMessage("message", []byte("hello"))
+
+	assert.Equal(t, t1.ChallengeBytes("challenge", 32), t2.ChallengeBytes("challenge", 32))
+	assert.Equal(t, t1.ChallengeIndex("index", 1000), t2.ChallengeIndex("index", 1000))
+}
+
+func TestTranscriptSeparation(t *testing.T) {
+	challenge := func(domain, label string, message []byte, challengeLabel string) []byte {
+		transcript := NewTranscript(domain)
+		transcript.AppendMessage(label, message)
+		return transcript.ChallengeBytes(challengeLabel, 32)
+	}
+
+	base := challenge("test", "message", []byte("hello"), "challenge")
+	assert.NotEqual(t, base, challenge("other", "message", []byte("hello"), "challenge"), "Domain should change the challenge")
+	assert.NotEqual(t, base, challenge("test", "other", []byte("hello"), "challenge"), "Message label should change the challenge")
+	assert.NotEqual(t, base, challenge("test", "message", []byte("hello!"), "challenge"), "Message should change the challenge")
+	assert.NotEqual(t, base, challenge("test", "message", []byte("hello"), "other"), "Challenge label should change the challenge")
+
+	// Moving bytes between the label and the message must not collide
+	assert.NotEqual(t, challenge("test", "ab", []byte("c"), "challenge"), challenge("test", "a", []byte("bc"), "challenge"))
+}
+
+func TestTranscriptChallengesChain(t *testing.T) {
+	transcript := NewTranscript("test")
+	first := transcript.ChallengeBytes("challenge", 32)
+	second := transcript.ChallengeBytes("challenge", 32)
+	assert.NotEqual(t, first, second, "Consecutive challenges should differ")
+}
+
+func TestTranscriptChallengeIndexRange(t *testing.T) {
+	transcript := NewTranscript("test")
+
+	counts := make([]int, 3)
+	for range 3000 {
+		index := transcript.ChallengeIndex("index", 3)
+		assert.Less(t, index, uint64(3))
+		counts[index]++
+	}
+
+	// Every index should come up roughly a third of the time
+	for i, count := range counts {
+		assert.InDelta(t, 1000, count, 150, "index %d came up %d times", i, count)
+	}
+
+	assert.Equal(t, uint64(0), transcript.ChallengeIndex("index", 1))
+	assert.Panics(t, func() { transcript.ChallengeIndex("index", 0) })
+}
+
+func TestEdgeChallengesDependOnStatement(t *testing.T) {
+	commitments := []CommitementGraphPayload{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}
+
+	first := edgeChallenges(hashing.SHA256, []byte{1}, commitments, 1<<32)
+	assert.Equal(t, first, edgeChallenges(hashing.SHA256, []byte{1}, commitments, 1<<32))
+	assert.NotEqual(t, first, edgeChallenges(hashing.SHA256, []byte{2}, commitments, 1<<32))
+}
+
+func TestEdgeChallengesDependOnHashAlgorithm(t *testing.T) {
+	commitments := []CommitementGraphPayload{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}
+
+	first := edgeChallenges(hashing.SHA256, []byte{1}, commitments, 1<<32)
+	assert.NotEqual(t, first, edgeChallenges(hashing.SHA3_256, []byte{1}, commitments, 1<<32))
+}
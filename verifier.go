@@ -1,80 +1,338 @@
 package zkp
 
 import (
-	"crypto/sha1"
+	"bytes"
+	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 
 	coloredgraph "github.com/hvuhsg/zkp/coloring_graph"
 	graph "github.com/hvuhsg/zkp/graph"
+	"github.com/hvuhsg/zkp/hashing"
 )
 
+var (
+	ErrHashNotAllowed      = errors.New("hash algorithm not allowed by the verifier policy")
+	ErrMalformedProof      = errors.New("malformed proof")
+	ErrStatementMismatch   = errors.New("proof is not about the public graph")
+	ErrNoEdges             = errors.New("graph has no edges")
+	ErrMalformedCommitment = errors.New("malformed commitment graph")
+	ErrChallengeMismatch   = errors.New("opened edge is not the challenged edge")
+	ErrOpeningMismatch     = errors.New("opened values do not match the commitments")
+	ErrSameColor           = errors.New("opened edge has the same color on both ends")
+)
+
+// RoundError is returned when a single round of a proof fails.
+type RoundError struct {
+	Round int
+	Err   error
+}
+
+func (e *RoundError) Error() string {
+	return fmt.Sprintf("round %d: %v", e.Round, e.Err)
+}
+
+func (e *RoundError) Unwrap() error {
+	return e.Err
+}
+
+// VerificationReport describes a proof that passed verification.
+type VerificationReport struct {
+	HashAlgorithm hashing.Algorithm
+	Nodes         int
+	Edges         int
+
+	// SoundnessBits is the soundness level the rounds of the proof reach:
+	// a cheating prover passes with probability at most 2^-SoundnessBits.
+	SoundnessBits float64
+
+	// Rounds has one entry per round of the proof, in order.
+	Rounds []RoundReport
+}
+
+// RoundReport describes the edge opened by a round.
+type RoundReport struct {
+	Edge uint64
+	From int
+	To   int
+}
+
+// Verifier checks proofs according to its configured policy.
+type Verifier struct {
+	hashPolicy  hashing.Policy
+	concurrency int
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithHashPolicy sets the hash algorithms the verifier accepts.
+// The default is hashing.DefaultPolicy.
+func WithHashPolicy(policy hashing.Policy) VerifierOption {
+	return func(v *Verifier) {
+		v.hashPolicy = policy
+	}
+}
+
+// WithVerifierConcurrency sets the number of rounds checked at the same time.
+// A value below 1 means runtime.GOMAXPROCS(0), which is the default.
+func WithVerifierConcurrency(n int) VerifierOption {
+	return func(v *Verifier) {
+		v.concurrency = n
+	}
+}
+
+func NewVerifier(opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		hashPolicy: hashing.DefaultPolicy(),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.concurrency < 1 {
+		v.concurrency = runtime.GOMAXPROCS(0)
+	}
+	return v
+}
+
+// Verify checks the proof with the default verifier.
+//
+// Verify does not know which graph the prover was supposed to use. Use
+// VerifyStatement to check the proof against a public graph.
 func (p *Proof) Verify() bool {
-	// Deserialize the commitment graphs
-	graphs := make([]*graph.Graph[coloredgraph.ColorNodeValue], len(p.commitementGraphs))
-	for i, cg := range p.commitementGraphs {
-		var err error
-		graphs[i], err = graph.DeserializeGraph(cg, coloredgraph.DeserializeColorNodeValue)
-		if err != nil {
-			return false
-		}
+	return NewVerifier().Verify(p)
+}
+
+// VerifyStatement checks the proof against the public graph with the default
+// verifier.
+func (p *Proof) VerifyStatement(public *coloredgraph.ColoringGraph) bool {
+	return NewVerifier().VerifyStatement(p, public)
+}
+
+// VerifyDetailed is like Verify but tells why a proof is rejected.
+func (p *Proof) VerifyDetailed() (*VerificationReport, error) {
+	return NewVerifier().VerifyDetailed(p)
+}
+
+// VerifyStatementDetailed is like VerifyStatement but tells why a proof is
+// rejected.
+func (p *Proof) VerifyStatementDetailed(public *coloredgraph.ColoringGraph) (*VerificationReport, error) {
+	return NewVerifier().VerifyStatementDetailed(p, public)
+}
+
+// Verify checks that every round commits to the same graph as the proof's
+// statement and that every opened edge is properly colored.
+func (v *Verifier) Verify(p *Proof) bool {
+	_, err := v.VerifyDetailed(p)
+	return err == nil
+}
+
+// VerifyStatement verifies the proof and checks that every round commits to
+// the same graph as the public graph, as identified by graph.Graph.Fingerprint.
+// The node values of the public graph and the order of its edges are ignored.
+func (v *Verifier) VerifyStatement(p *Proof, public *coloredgraph.ColoringGraph) bool {
+	_, err := v.VerifyStatementDetailed(p, public)
+	return err == nil
+}
+
+// VerifyDetailed is like Verify but returns a report of the accepted proof, or
+// an error telling why the proof is rejected. Errors about a single round are
+// *RoundError values wrapping one of the Err* sentinels.
+func (v *Verifier) VerifyDetailed(p *Proof) (*VerificationReport, error) {
+	return v.verify(p, nil)
+}
+
+// VerifyStatementDetailed is like VerifyStatement but returns a report of the
+// accepted proof, or an error telling why the proof is rejected.
+func (v *Verifier) VerifyStatementDetailed(p *Proof, public *coloredgraph.ColoringGraph) (*VerificationReport, error) {
+	if public == nil {
+		return nil, fmt.Errorf("%w: no public graph", ErrStatementMismatch)
+	}
+	if !bytes.Equal(statementFingerprint(public.Graph), p.statement) {
+		return nil, fmt.Errorf("%w: fingerprints differ", ErrStatementMismatch)
 	}
 
-	randomizer := NewRandomizerFromCommitments(p.commitementGraphs)
+	return v.verify(p, public.Graph)
+}
 
-	// Verify the proof
-	for i, g := range graphs {
-		edgeNonce := randomizer.Uint64()
+func (v *Verifier) verify(p *Proof, public *graph.Graph[coloredgraph.ColorNodeValue]) (*VerificationReport, error) {
+	if !v.hashPolicy.Allows(p.hashAlgorithm) {
+		return nil, fmt.Errorf("%w: %v", ErrHashNotAllowed, p.hashAlgorithm)
+	}
 
-		// Verify edge values are not the same
-		if !isEdgeValuesValid(p.edgeValues[i]) {
-			return false
-		}
+	// A proof without rounds proves nothing, anyone can make one for any graph
+	if len(p.commitementGraphs) == 0 {
+		return nil, fmt.Errorf("%w: no rounds", ErrMalformedProof)
+	}
+	if len(p.edgeIds) != len(p.commitementGraphs) || len(p.edgeValues) != len(p.commitementGraphs) {
+		return nil, fmt.Errorf("%w: rounds have different lengths", ErrMalformedProof)
+	}
 
-		edges := g.GetEdges()
+	// Every round must match the first one, whose fingerprint is the statement.
+	// The challenges index the edges in the prover's order, so the first round
+	// is the reference even when the public graph is known: the public graph
+	// can list the same edges in another order.
+	opts := graph.DecodeOptions{MaxValueSize: 2 * p.hashAlgorithm.Size()}
+	publicEdges := 0
+	if public != nil {
+		publicEdges = distinctEdges(public)
+		opts.MaxNodes = len(public.GetNodes())
+		opts.MaxEdges = publicEdges
+	}
+
+	reference, err := graph.DeserializeGraphWithOptions(p.commitementGraphs[0], coloredgraph.DeserializeColorNodeValue, opts)
+	if err != nil {
+		return nil, &RoundError{Round: 0, Err: fmt.Errorf("%w: %w", ErrMalformedCommitment, err)}
+	}
+	if !bytes.Equal(statementFingerprint(reference), p.statement) {
+		return nil, fmt.Errorf("%w: fingerprints differ", ErrStatementMismatch)
+	}
 
-		// Verify the edge id is valid
-		if !isExpectedEdgeIdValid(len(edges), edgeNonce, p.edgeIds[i]) {
-			return false
+	// The fingerprint ignores repeated edges, so a reference graph could pad
+	// the public edges with copies of a properly colored edge and make the
+	// challenges miss the badly colored ones
+	if err := reference.Validate(); err != nil {
+		if errors.Is(err, graph.ErrDuplicateEdge) {
+			return nil, fmt.Errorf("%w: %w", ErrStatementMismatch, err)
 		}
+		return nil, &RoundError{Round: 0, Err: fmt.Errorf("%w: %w", ErrMalformedCommitment, err)}
+	}
+	if public != nil && len(reference.GetEdges()) != publicEdges {
+		return nil, fmt.Errorf("%w: %d edges, public graph has %d", ErrStatementMismatch, len(reference.GetEdges()), publicEdges)
+	}
+
+	report := &VerificationReport{
+		HashAlgorithm: p.hashAlgorithm,
+		Nodes:         len(reference.GetNodes()),
+		Edges:         len(reference.GetEdges()),
+		Rounds:        make([]RoundReport, len(p.commitementGraphs)),
+	}
 
-		nodes := g.GetNodes()
-		edge := edges[p.edgeIds[i]]
-		node1 := nodes[edge.From]
-		node2 := nodes[edge.To]
+	// There is no edge to open in a graph without edges
+	if report.Edges == 0 {
+		return nil, ErrNoEdges
+	}
 
-		edgeValue1Hash := sha1.Sum([]byte(p.edgeValues[i][0]))
-		edgeValue2Hash := sha1.Sum([]byte(p.edgeValues[i][1]))
-		edgeValue1HashString := hex.EncodeToString(edgeValue1Hash[:])
-		edgeValue2HashString := hex.EncodeToString(edgeValue2Hash[:])
+	// The challenges only depend on the payloads, derive them once for all rounds
+	challenges := edgeChallenges(p.hashAlgorithm, p.statement, p.commitementGraphs, report.Edges)
 
-		// verify hash of edge values is the same as nodes values
-		if string(node1.Value) != edgeValue1HashString || string(node2.Value) != edgeValue2HashString {
-			return false
+	// A commitment graph can not be larger than the reference graph
+	decodeOptions := graph.DecodeOptions{
+		MaxNodes:     report.Nodes,
+		MaxEdges:     report.Edges,
+		MaxValueSize: 2 * p.hashAlgorithm.Size(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Keep the failure of the earliest round among the rounds that were checked
+	var mu sync.Mutex
+	var failure *RoundError
+
+	rounds := make(chan int)
+	var wg sync.WaitGroup
+	for range min(v.concurrency, len(p.commitementGraphs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				if ctx.Err() != nil {
+					continue
+				}
+
+				roundReport, err := v.verifyRound(p, i, reference, challenges[i], decodeOptions)
+				if err != nil {
+					mu.Lock()
+					if failure == nil || i < failure.Round {
+						failure = &RoundError{Round: i, Err: err}
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				report.Rounds[i] = roundReport
+			}
+		}()
+	}
+
+	// Stop handing out rounds as soon as one fails
+dispatch:
+	for i := range p.commitementGraphs {
+		select {
+		case rounds <- i:
+		case <-ctx.Done():
+			break dispatch
 		}
 	}
-	return true
+	close(rounds)
+	wg.Wait()
+
+	if failure != nil {
+		return nil, failure
+	}
+
+	report.SoundnessBits = SoundnessBits(report.Edges, len(p.commitementGraphs))
+	return report, nil
 }
 
-func isEdgeValuesValid(edgeValues [2]string) bool {
+// verifyRound checks that a round commits to the reference graph and opens the
+// challenged edge with two different colors.
+func (v *Verifier) verifyRound(p *Proof, i int, reference *graph.Graph[coloredgraph.ColorNodeValue], challenge uint64, decodeOptions graph.DecodeOptions) (RoundReport, error) {
+	// Verify the opened edge is the one the challenge asked for
+	if p.edgeIds[i] != challenge {
+		return RoundReport{}, fmt.Errorf("%w: opened edge %d, challenged edge %d", ErrChallengeMismatch, p.edgeIds[i], challenge)
+	}
+
+	g, err := graph.DeserializeGraphWithOptions(p.commitementGraphs[i], coloredgraph.DeserializeColorNodeValue, decodeOptions)
+	if err != nil {
+		return RoundReport{}, fmt.Errorf("%w: %w", ErrMalformedCommitment, err)
+	}
+
+	if !sameStructure(reference, g) {
+		return RoundReport{}, fmt.Errorf("%w: commitment graph has another structure", ErrStatementMismatch)
+	}
+
+	nodes := g.GetNodes()
+	edge := g.GetEdges()[p.edgeIds[i]]
+	node1 := nodes[edge.From]
+	node2 := nodes[edge.To]
+
+	edgeValue1HashString := hex.EncodeToString(p.hashAlgorithm.Sum([]byte(p.edgeValues[i][0])))
+	edgeValue2HashString := hex.EncodeToString(p.hashAlgorithm.Sum([]byte(p.edgeValues[i][1])))
+
+	// verify hash of edge values is the same as nodes values
+	if string(node1.Value) != edgeValue1HashString || string(node2.Value) != edgeValue2HashString {
+		return RoundReport{}, ErrOpeningMismatch
+	}
+
+	// Verify edge values are not the same
+	if err := checkEdgeValues(p.edgeValues[i]); err != nil {
+		return RoundReport{}, err
+	}
+
+	return RoundReport{Edge: p.edgeIds[i], From: edge.From, To: edge.To}, nil
+}
+
+// checkEdgeValues checks that both opened values are well formed and have
+// different colors.
+func checkEdgeValues(edgeValues [2]string) error {
 	color1, err := getColorFromNodeValue(edgeValues[0])
 	if err != nil {
-		return false
+		return fmt.Errorf("%w: %w", ErrOpeningMismatch, err)
 	}
 	color2, err := getColorFromNodeValue(edgeValues[1])
 	if err != nil {
-		return false
+		return fmt.Errorf("%w: %w", ErrOpeningMismatch, err)
 	}
 	if color1 == color2 {
-		return false
+		return ErrSameColor
 	}
-	return true
-}
-
-func isExpectedEdgeIdValid(edgesCount int, edgeNonce uint64, edgeId uint64) bool {
-	expectedEdgeId := edgeNonce % uint64(edgesCount)
-	return expectedEdgeId == edgeId
+	return nil
 }
 
 func getColorFromNodeValue(nodeValue string) (string, error) {